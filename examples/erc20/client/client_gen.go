@@ -5,6 +5,7 @@ import (
 	"bytes"
 	context "context"
 	contract "erc20/contract"
+	"errors"
 	"fmt"
 
 	address "github.com/filecoin-project/go-address"
@@ -15,7 +16,9 @@ import (
 	actors "github.com/filecoin-project/venus/venus-shared/actors"
 	blockstore "github.com/filecoin-project/venus/venus-shared/blockstore"
 	types "github.com/filecoin-project/venus/venus-shared/types"
+	sdk "github.com/ipfs-force-community/go-fvm-sdk/sdk"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/adt"
+	ferrors "github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
@@ -204,7 +207,7 @@ func (c *Erc20TokenClient) CreateActor(ctx context.Context, codeCid cid.Cid, cto
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 
 	var result init_.ExecReturn
@@ -394,7 +397,7 @@ func (c *Erc20TokenClient) Transfer(ctx context.Context, p0 *contract.TransferRe
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
@@ -433,7 +436,7 @@ func (c *Erc20TokenClient) TransferFrom(ctx context.Context, p0 *contract.Transf
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
@@ -472,7 +475,86 @@ func (c *Erc20TokenClient) Approval(ctx context.Context, p0 *contract.ApprovalRe
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
+
+// EventSource streams the events emitted by an actor from height fromHeight. The venus node of the client has no
+// api returning actor events, its message receipts don't carry them, so the caller supplies the source, eg. an
+// adapter over a node serving the events of FIP-0049 filtered on the emitter address from fromHeight.
+type EventSource interface {
+	ActorEvents(ctx context.Context, emitter address.Address, fromHeight abi.ChainEpoch) (<-chan *sdkTypes.EmittedEvent, error)
+}
+
+// Erc20TokenEvent an event of the actor decoded into one of its declared event types.
+type Erc20TokenEvent struct {
+	*sdkTypes.EmittedEvent
+	// Event the decoded event, eg. *contract.TransferEvent, nil if Err is set
+	Event interface{}
+	// Err the error decoding an event of a declared type, eg. a malformed entry
+	Err error
+}
+
+// DecodeTransferEvent decodes a TransferEvent event of the actor, eg. from a message receipt.
+func DecodeTransferEvent(evt sdkTypes.ActorEvent) (*contract.TransferEvent, error) {
+	out, err := sdk.DecodeEvent[contract.TransferEvent](evt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecodeApprovalEvent decodes a ApprovalEvent event of the actor, eg. from a message receipt.
+func DecodeApprovalEvent(evt sdkTypes.ActorEvent) (*contract.ApprovalEvent, error) {
+	out, err := sdk.DecodeEvent[contract.ApprovalEvent](evt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecodeEvent decodes an event of the actor into one of its declared event types, returns sdk.ErrEventMismatch
+// for other events.
+func DecodeEvent(evt sdkTypes.ActorEvent) (interface{}, error) {
+	if out, err := DecodeTransferEvent(evt); err == nil {
+		return out, nil
+	} else if !errors.Is(err, sdk.ErrEventMismatch) {
+		return nil, err
+	}
+	if out, err := DecodeApprovalEvent(evt); err == nil {
+		return out, nil
+	} else if !errors.Is(err, sdk.ErrEventMismatch) {
+		return nil, err
+	}
+	return nil, sdk.ErrEventMismatch
+}
+
+// SubscribeEvents streams the decoded events of the actor from height fromHeight, events which aren't of a
+// declared event type are skipped. An event of a declared type which fails to decode is streamed with Err set.
+func (c *Erc20TokenClient) SubscribeEvents(ctx context.Context, source EventSource, fromHeight abi.ChainEpoch) (<-chan *Erc20TokenEvent, error) {
+	if c.cfg.actor == address.Undef {
+		return nil, fmt.Errorf("unset actor address for subscribe")
+	}
+	raw, err := source.ActorEvents(ctx, c.cfg.actor, fromHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe actor events: %w", err)
+	}
+
+	out := make(chan *Erc20TokenEvent)
+	go func() {
+		defer close(out)
+		for evt := range raw {
+			decoded, err := DecodeEvent(sdkTypes.ActorEvent{Entries: evt.Entries})
+			if errors.Is(err, sdk.ErrEventMismatch) {
+				continue
+			}
+			select {
+			case out <- &Erc20TokenEvent{EmittedEvent: evt, Event: decoded, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -90,6 +90,28 @@ func (t *Erc20Token) Export() []interface{} {
 	}
 }
 
+// Events declares the events emitted by the token, clients get decoders for them.
+func (t *Erc20Token) Events() []interface{} {
+	return []interface{}{
+		TransferEvent{},
+		ApprovalEvent{},
+	}
+}
+
+// TransferEvent emitted when tokens are transferred.
+type TransferEvent struct {
+	From   abi.ActorID     `event:"from,indexed"`
+	To     abi.ActorID     `event:"to,indexed"`
+	Amount abi.TokenAmount `event:"amount"`
+}
+
+// ApprovalEvent emitted when an owner changes the allowance of a spender.
+type ApprovalEvent struct {
+	Owner     abi.ActorID     `event:"owner,indexed"`
+	Spender   abi.ActorID     `event:"spender,indexed"`
+	Allowance abi.TokenAmount `event:"allowance"`
+}
+
 type ConstructorReq struct {
 	Name        string
 	Symbol      string
@@ -261,7 +283,7 @@ func (t *Erc20Token) Transfer(ctx context.Context, transferReq *TransferReq) err
 	t.Balances = newBalanceMapRoot
 	logger.Logf(ctx, "transfer from %d to %d amount %s", senderID, receiverID, transferReq.TransferAmount.String())
 	_ = sdk.SaveState(ctx, t)
-	return nil
+	return sdk.Emit(ctx, &TransferEvent{From: senderID, To: receiverID, Amount: transferReq.TransferAmount})
 }
 
 type AllowanceReq struct {
@@ -399,7 +421,7 @@ func (t *Erc20Token) TransferFrom(ctx context.Context, req *TransferFromReq) err
 		return err
 	}
 	_ = sdk.SaveState(ctx, t)
-	return nil
+	return sdk.Emit(ctx, &TransferEvent{From: tokenOwnerID, To: receiverID, Amount: req.TransferAmount})
 }
 
 type ApprovalReq struct {
@@ -435,7 +457,8 @@ func (t *Erc20Token) Approval(ctx context.Context, req *ApprovalReq) error {
 		return err
 	}
 
-	err = allowBalanceMap.Put(types.StringKey(getAllowKey(callerID, spenderID)), add(allowance, &req.NewAllowance))
+	newAllowance := add(allowance, &req.NewAllowance)
+	err = allowBalanceMap.Put(types.StringKey(getAllowKey(callerID, spenderID)), newAllowance)
 	if err != nil {
 		return err
 	}
@@ -445,7 +468,7 @@ func (t *Erc20Token) Approval(ctx context.Context, req *ApprovalReq) error {
 	}
 	_ = sdk.SaveState(ctx, t)
 	logger.Logf(ctx, "approval %s for %s", getAllowKey(callerID, spenderID), req.NewAllowance.String())
-	return nil
+	return sdk.Emit(ctx, &ApprovalEvent{Owner: callerID, Spender: spenderID, Allowance: *newAllowance})
 }
 
 /*checkBalance checks if sender's balance is >= 0*/
@@ -122,6 +122,12 @@ func TestErc20TokenTransfer(t *testing.T) {
 		toBalance, err := newState.BalanceOf(simulator.Context, &toAddr)
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(100), toBalance.Uint64())
+
+		events := simulator.Events()
+		assert.Len(t, events, 1)
+		transfer, err := sdk.DecodeEvent[TransferEvent](events[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "100", transfer.Amount.String())
 	})
 
 	t.Run("fail transfer zero", func(t *testing.T) {
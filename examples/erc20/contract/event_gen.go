@@ -0,0 +1,68 @@
+// Code generated by github.com/ipfs-force-community/go-fvm-sdk. DO NOT EDIT.
+package contract
+
+import (
+	abi "github.com/filecoin-project/go-state-types/abi"
+
+	sdk "github.com/ipfs-force-community/go-fvm-sdk/sdk"
+
+	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+)
+
+// MarshalEvent builds the entries of the TransferEvent event, see sdk.Emit.
+func (t *TransferEvent) MarshalEvent() (*sdkTypes.ActorEvent, error) {
+	w := sdk.NewEventWriter("TransferEvent")
+	w.Uint("from", sdkTypes.FLAGINDEXEDALL, uint64(t.From))
+	w.Uint("to", sdkTypes.FLAGINDEXEDALL, uint64(t.To))
+	w.Value("amount", 0, &t.Amount)
+	return w.Event()
+}
+
+// UnmarshalEvent decodes the TransferEvent event built by MarshalEvent, returns sdk.ErrEventMismatch for an event
+// of another type. Entries unknown to TransferEvent are ignored.
+func (t *TransferEvent) UnmarshalEvent(evt sdkTypes.ActorEvent) error {
+	return sdk.ReadEvent(evt, "TransferEvent", func(key string, value []byte) error {
+		switch key {
+		case "from":
+			v, err := sdk.ReadEventUint(value)
+			t.From = abi.ActorID(v)
+			return err
+		case "to":
+			v, err := sdk.ReadEventUint(value)
+			t.To = abi.ActorID(v)
+			return err
+		case "amount":
+			return sdk.ReadEventValue(value, &t.Amount)
+		}
+		return nil
+	})
+}
+
+// MarshalEvent builds the entries of the ApprovalEvent event, see sdk.Emit.
+func (t *ApprovalEvent) MarshalEvent() (*sdkTypes.ActorEvent, error) {
+	w := sdk.NewEventWriter("ApprovalEvent")
+	w.Uint("owner", sdkTypes.FLAGINDEXEDALL, uint64(t.Owner))
+	w.Uint("spender", sdkTypes.FLAGINDEXEDALL, uint64(t.Spender))
+	w.Value("allowance", 0, &t.Allowance)
+	return w.Event()
+}
+
+// UnmarshalEvent decodes the ApprovalEvent event built by MarshalEvent, returns sdk.ErrEventMismatch for an event
+// of another type. Entries unknown to ApprovalEvent are ignored.
+func (t *ApprovalEvent) UnmarshalEvent(evt sdkTypes.ActorEvent) error {
+	return sdk.ReadEvent(evt, "ApprovalEvent", func(key string, value []byte) error {
+		switch key {
+		case "owner":
+			v, err := sdk.ReadEventUint(value)
+			t.Owner = abi.ActorID(v)
+			return err
+		case "spender":
+			v, err := sdk.ReadEventUint(value)
+			t.Spender = abi.ActorID(v)
+			return err
+		case "allowance":
+			return sdk.ReadEventValue(value, &t.Allowance)
+		}
+		return nil
+	})
+}
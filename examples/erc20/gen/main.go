@@ -20,7 +20,11 @@ func main() {
 		log.Fatalf("gen for ../contract: %s", err)
 	}
 	stateT := reflect.TypeOf(contract.Erc20Token{})
-	err := gen.GenEntry(stateT, "../entry_gen.go")
+	err := gen.GenEvent(stateT, "../contract/event_gen.go")
+	if err != nil {
+		log.Fatalf("gen for event %s", err)
+	}
+	err = gen.GenEntry(stateT, "../entry_gen.go")
 	if err != nil {
 		log.Fatalf("gen for entry %s", err)
 	}
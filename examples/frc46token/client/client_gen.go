@@ -16,6 +16,7 @@ import (
 	blockstore "github.com/filecoin-project/venus/venus-shared/blockstore"
 	types "github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/adt"
+	ferrors "github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
@@ -213,7 +214,7 @@ func (c *Frc46TokenClient) CreateActor(ctx context.Context, codeCid cid.Cid, cto
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 
 	var result init_.ExecReturn
@@ -355,7 +356,7 @@ func (c *Frc46TokenClient) Mint(ctx context.Context, p0 *contract.MintParams, op
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -450,7 +451,7 @@ func (c *Frc46TokenClient) Transfer(ctx context.Context, p0 *contract.TransferPa
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -497,7 +498,7 @@ func (c *Frc46TokenClient) TransferFrom(ctx context.Context, p0 *contract.Transf
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -544,7 +545,7 @@ func (c *Frc46TokenClient) IncreaseAllowance(ctx context.Context, p0 *contract.I
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -591,7 +592,7 @@ func (c *Frc46TokenClient) DecreaseAllowance(ctx context.Context, p0 *contract.D
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -638,7 +639,7 @@ func (c *Frc46TokenClient) RevokeAllowance(ctx context.Context, p0 *contract.Rev
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -685,7 +686,7 @@ func (c *Frc46TokenClient) Burn(ctx context.Context, p0 *contract.BurnParams, op
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -732,7 +733,7 @@ func (c *Frc46TokenClient) BurnFrom(ctx context.Context, p0 *contract.BurnFromPa
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -37,6 +37,7 @@ func main() {}
 //go:export invoke
 func Invoke(blockId uint32) uint32 {
 	ctx := context.Background()
+	defer sdk.RecoverPanic(ctx)
 	method, err := sdk.MethodNumber(ctx)
 	if err != nil {
 		sdk.Abort(ctx, ferrors.USR_ILLEGAL_STATE, "unable to get method number")
@@ -262,8 +263,15 @@ func Invoke(blockId uint32) uint32 {
 	}
 
 	if err != nil {
+		var actorErr *ferrors.ActorError
+		if errors.As(err, &actorErr) {
+			sdk.Exit(ctx, actorErr.ExitCode(), actorErr.Data, actorErr.Error())
+		}
 		exitCode := ferrors.USR_ILLEGAL_STATE
-		errors.As(err, &exitCode)
+		if errors.As(err, &exitCode) && exitCode.IsSystemError() {
+			// codes reserved for the VM, eg. returned by a failed send, can't be used to abort
+			exitCode = ferrors.USR_UNSPECIFIED
+		}
 		sdk.Abort(ctx, exitCode, fmt.Sprintf("call error %s", err))
 	}
 
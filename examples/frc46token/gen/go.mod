@@ -9,6 +9,7 @@ require (
 
 replace (
 	frc46token => ../
+	github.com/filecoin-project/go-address => github.com/ipfs-force-community/go-address v0.0.7-0.20230207015848-7a27d889c267
 	github.com/ipfs-force-community/go-fvm-sdk => ../../..
 	github.com/ipfs-force-community/go-fvm-sdk/gen => ../../../gen
 )
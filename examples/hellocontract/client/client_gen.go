@@ -14,6 +14,7 @@ import (
 	actors "github.com/filecoin-project/venus/venus-shared/actors"
 	blockstore "github.com/filecoin-project/venus/venus-shared/blockstore"
 	types "github.com/filecoin-project/venus/venus-shared/types"
+	ferrors "github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
@@ -181,7 +182,7 @@ func (c *StateClient) CreateActor(ctx context.Context, codeCid cid.Cid, opts ...
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 
 	var result init_.ExecReturn
@@ -222,7 +223,7 @@ func (c *StateClient) SayHello(ctx context.Context, opts ...SendOption) (sdkType
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -14,6 +14,7 @@ import (
 	actors "github.com/filecoin-project/venus/venus-shared/actors"
 	blockstore "github.com/filecoin-project/venus/venus-shared/blockstore"
 	types "github.com/filecoin-project/venus/venus-shared/types"
+	ferrors "github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
@@ -183,7 +184,7 @@ func (c *StateClient) CreateActor(ctx context.Context, codeCid cid.Cid, opts ...
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 
 	var result init_.ExecReturn
@@ -224,7 +225,7 @@ func (c *StateClient) GetBalance(ctx context.Context, opts ...SendOption) (*big.
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return nil, fmt.Errorf("expect get result for call")
@@ -271,7 +272,7 @@ func (c *StateClient) Withdraw(ctx context.Context, p0 *big.Int, opts ...SendOpt
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
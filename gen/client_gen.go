@@ -163,6 +163,12 @@ func GenContractClient(stateT reflect.Type, output string) error {
 		}
 	}
 
+	if len(entryMeta.Events) > 0 {
+		if err = genClientEvents(buf, *entryMeta); err != nil {
+			return err
+		}
+	}
+
 	return formateAndWriteCode(buf.Bytes(), output)
 }
 
@@ -358,7 +364,7 @@ func genClientConstructor(w io.Writer, meta entryMeta) error {
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return nil, fmt.Errorf("actor execution failed")
+		return nil, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 
 	var result init_.ExecReturn
@@ -444,7 +450,7 @@ func (c *{{trimPackage .StateName}}Client) {{.FuncName}}(ctx context.Context, p0
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return {{.DefaultReturn|raw}}, fmt.Errorf("actor execution failed")
+		return {{.DefaultReturn|raw}}, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return {{.DefaultReturn|raw}}, fmt.Errorf("expect get result for call")
@@ -504,7 +510,7 @@ func (c *{{trimPackage .StateName}}Client) {{.FuncName}}(ctx context.Context, p0
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
@@ -549,7 +555,7 @@ func (c *{{trimPackage .StateName}}Client) {{.FuncName}}(ctx context.Context, op
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return {{.DefaultReturn|raw}}, fmt.Errorf("actor execution failed")
+		return {{.DefaultReturn|raw}}, &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	if len(wait.Receipt.Return) == 0 {
 		return {{.DefaultReturn|raw}}, fmt.Errorf("expect get result for call")
@@ -604,7 +610,7 @@ func (c *{{trimPackage .StateName}}Client) {{.FuncName}}(ctx context.Context, op
 
 	// check it executed successfully
 	if wait.Receipt.ExitCode != 0 {
-		return fmt.Errorf("actor execution failed")
+		return &ferrors.ActorError{Code: ferrors.ExitCode(wait.Receipt.ExitCode), Message: "actor execution failed", Data: wait.Receipt.Return}
 	}
 	return nil
 }
@@ -652,3 +658,78 @@ func (c *{{trimPackage .StateName}}Client) {{.FuncName}}(ctx context.Context{{if
 
 	return render.Execute(w, entry)
 }
+
+func genClientEvents(w io.Writer, meta entryMeta) error {
+	tpl := `
+// EventSource streams the events emitted by an actor from height fromHeight. The venus node of the client has no
+// api returning actor events, its message receipts don't carry them, so the caller supplies the source, eg. an
+// adapter over a node serving the events of FIP-0049 filtered on the emitter address from fromHeight.
+type EventSource interface {
+	ActorEvents(ctx context.Context, emitter address.Address, fromHeight abi.ChainEpoch) ({{raw "<-"}}chan *sdkTypes.EmittedEvent, error)
+}
+
+// {{trimPackage .StateName}}Event an event of the actor decoded into one of its declared event types.
+type {{trimPackage .StateName}}Event struct {
+	*sdkTypes.EmittedEvent
+	// Event the decoded event, eg. *{{(index .Events 0).TypeName}}, nil if Err is set
+	Event interface{}
+	// Err the error decoding an event of a declared type, eg. a malformed entry
+	Err error
+}
+{{range .Events}}
+// Decode{{.ShortName}} decodes a {{.ShortName}} event of the actor, eg. from a message receipt.
+func Decode{{.ShortName}}(evt sdkTypes.ActorEvent) (*{{.TypeName}}, error) {
+	out, err := sdk.DecodeEvent[{{.TypeName}}](evt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+{{end}}
+// DecodeEvent decodes an event of the actor into one of its declared event types, returns sdk.ErrEventMismatch
+// for other events.
+func DecodeEvent(evt sdkTypes.ActorEvent) (interface{}, error) {
+	{{range .Events}}if out, err := Decode{{.ShortName}}(evt); err == nil {
+		return out, nil
+	} else if !errors.Is(err, sdk.ErrEventMismatch) {
+		return nil, err
+	}
+	{{end}}return nil, sdk.ErrEventMismatch
+}
+
+// SubscribeEvents streams the decoded events of the actor from height fromHeight, events which aren't of a
+// declared event type are skipped. An event of a declared type which fails to decode is streamed with Err set.
+func (c *{{trimPackage .StateName}}Client) SubscribeEvents(ctx context.Context, source EventSource, fromHeight abi.ChainEpoch) ({{raw "<-"}}chan *{{trimPackage .StateName}}Event, error) {
+	if c.cfg.actor == address.Undef {
+		return nil, fmt.Errorf("unset actor address for subscribe")
+	}
+	raw, err := source.ActorEvents(ctx, c.cfg.actor, fromHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe actor events: %w", err)
+	}
+
+	out := make(chan *{{trimPackage .StateName}}Event)
+	go func() {
+		defer close(out)
+		for evt := range raw {
+			decoded, err := DecodeEvent(sdkTypes.ActorEvent{Entries: evt.Entries})
+			if errors.Is(err, sdk.ErrEventMismatch) {
+				continue
+			}
+			select {
+			case out {{raw "<-"}} &{{trimPackage .StateName}}Event{EmittedEvent: evt, Event: decoded, Err: err}:
+			case {{raw "<-"}}ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+`
+	render, err := template.New("gen client events").Funcs(funcs).Parse(tpl)
+	if err != nil {
+		return err
+	}
+
+	return render.Execute(w, meta)
+}
@@ -25,6 +25,7 @@ import (
 
 var (
 	unMarshallerT       = reflect.TypeOf((*cbor.Unmarshaler)(nil)).Elem()
+	lazyFieldT          = reflect.TypeOf((*interface{ IsDirty() bool })(nil)).Elem()
 	errorT              = reflect.TypeOf((*error)(nil)).Elem()
 	marshallerT         = reflect.TypeOf((*cbor.Marshaler)(nil)).Elem()
 	knownPackageNamesMu sync.Mutex
@@ -113,6 +114,7 @@ func getEntryPackageMeta(pkg string, stateT reflect.Type) (*entryMeta, error) {
 
 	var methodsArr []*methodMap
 	hasParam := false
+	hasNonReentrant := false
 	typesToImport := []reflect.Type{stateT}
 	for _, actorMethod := range exports {
 		var method = &methodMap{}
@@ -124,6 +126,11 @@ func getEntryPackageMeta(pkg string, stateT reflect.Type) (*entryMeta, error) {
 			method.AliasName = methodValue.FieldByName("Alias").String()
 			actorFunc = methodValue.FieldByName("Func").Interface()
 			method.Readonly = methodValue.FieldByName("Readonly").Bool()
+			method.NonReentrant = methodValue.FieldByName("NonReentrant").Bool()
+			if method.Readonly && method.NonReentrant {
+				return nil, fmt.Errorf("func %v can not be both readonly and non-reentrant", method.AliasName)
+			}
+			hasNonReentrant = hasNonReentrant || method.NonReentrant
 		}
 
 		functionT := reflect.TypeOf(actorFunc)
@@ -201,6 +208,17 @@ func getEntryPackageMeta(pkg string, stateT reflect.Type) (*entryMeta, error) {
 
 	}
 
+	// events declared next to Export
+	eventTypes, err := getEvents(stateT)
+	if err != nil {
+		return nil, err
+	}
+	var events []*eventMeta
+	for _, evtT := range eventTypes {
+		events = append(events, &eventMeta{EventType: evtT})
+		typesToImport = append(typesToImport, evtT)
+	}
+
 	//resolve package and name
 	imports := defaultClientImport
 	for _, importType := range typesToImport {
@@ -229,16 +247,36 @@ func getEntryPackageMeta(pkg string, stateT reflect.Type) (*entryMeta, error) {
 		m.MethodNum = uint64(hashNumber)
 		fmt.Println("Method:", m.FuncName, " MethodNumber: ", hashNumber)
 	}
+	for _, evt := range events {
+		evt.TypeName = typeName(pkg, evt.EventType)
+		evt.ShortName = evt.EventType.Name()
+	}
 
 	return &entryMeta{
 		Imports: dedupImports(imports),
 		//PkgName:   ,
-		HasParam:  hasParam,
-		Methods:   methodsArr,
-		StateName: stateName,
+		HasParam:        hasParam,
+		Methods:         methodsArr,
+		StateName:       stateName,
+		LazyFields:      getLazyFields(stateT),
+		HasNonReentrant: hasNonReentrant,
+		Events:          events,
 	}, nil
 }
 
+// getLazyFields returns the state fields loaded on first use, eg. adt.LazyMap, the state is saved after a method
+// returns if any of them was loaded for modification.
+func getLazyFields(stateT reflect.Type) []string {
+	var fields []string
+	for i := 0; i < stateT.NumField(); i++ {
+		f := stateT.Field(i)
+		if f.PkgPath == "" && reflect.PtrTo(f.Type).Implements(lazyFieldT) {
+			fields = append(fields, f.Name)
+		}
+	}
+	return fields
+}
+
 func typeName(pkg string, t reflect.Type) string {
 	switch t.Kind() {
 	case reflect.Array:
@@ -342,20 +380,33 @@ type entryMeta struct {
 	Methods   []*methodMap
 	StateName string
 	StateType reflect.Type
+	// LazyFields the state fields loaded on first use
+	LazyFields []string
+	// HasNonReentrant whether any method locks the state root while it runs
+	HasNonReentrant bool
+	// Events the event types declared by the Events method of the state
+	Events []*eventMeta
+}
+
+type eventMeta struct {
+	EventType reflect.Type
+	TypeName  string
+	ShortName string
 }
 
 type methodMap struct {
-	StateName  string
-	MethodNum  uint64
-	FuncT      reflect.Value
-	PkgName    string
-	FuncName   string
-	AliasName  string
-	Readonly   bool
-	HasError   bool
-	HasParam   bool
-	HasReturn  bool
-	HasContext bool
+	StateName    string
+	MethodNum    uint64
+	FuncT        reflect.Value
+	PkgName      string
+	FuncName     string
+	AliasName    string
+	Readonly     bool
+	NonReentrant bool
+	HasError     bool
+	HasParam     bool
+	HasReturn    bool
+	HasContext   bool
 
 	ParamsType     reflect.Type
 	ParamsTypeName string
@@ -390,13 +441,16 @@ func main() {}
 //go:export invoke
 func Invoke(blockId uint32) uint32 {
 	ctx:=context.Background()
+	defer sdk.RecoverPanic(ctx)
 	method, err := sdk.MethodNumber(ctx)
 	if err != nil {
 		sdk.Abort(ctx,ferrors.USR_ILLEGAL_STATE, "unable to get method number")
 	}
 
 	var callResult cbor.Marshaler
-{{if .HasParam}}var raw *sdkTypes.ParamsRaw{{end}}
+{{if .HasParam}}var raw *sdkTypes.ParamsRaw{{end}}{{if .LazyFields}}
+	var lazyState *{{.StateName}}{{end}}{{if .HasNonReentrant}}
+	var nonReentrant bool{{end}}
 	switch method {
 {{range .Methods}}case {{.MethodNum|hex}}://{{.MethodNum}}  function name:{{.FuncName}}  alias name:{{.AliasName}}
 {{if eq .MethodNum 1}}  // Constuctor
@@ -426,24 +480,36 @@ func Invoke(blockId uint32) uint32 {
 								}
        		 {{if .HasError}}
 					 {{if .HasReturn}} // have params/return/error
-								state := new({{.StateName}})
-								sdk.LoadState(ctx,state)
+								{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+								sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 								callResult, err = state.{{.FuncName}}({{if .HasContext}} ctx, {{end}}&req)
 				     {{else}} 	// have params/error but no return val
-								state := new({{.StateName}})
-								sdk.LoadState(ctx,state)
+								{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+								sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 								if err = state.{{.FuncName}}({{if .HasContext}} ctx, {{end}}&req); err == nil {
 									callResult = typegen.CborBool(true)
 								}
 					{{end}}
 			{{else}}
 					{{if .HasReturn}}// have params/return but no error
-							state := new({{.StateName}})
-							sdk.LoadState(ctx,state)
+							{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+							sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 							callResult = state.{{.FuncName}}({{if .HasContext}} ctx, {{end}}&req)
 					{{else}}//have params but no return value and error
-							state := new({{.StateName}})
-							sdk.LoadState(ctx,state)
+							{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+							sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 							state.{{.FuncName}}({{if .HasContext}} ctx, {{end}}&req)
 							callResult = = typegen.CborBool(true)
 					{{end}}
@@ -451,24 +517,36 @@ func Invoke(blockId uint32) uint32 {
     {{else}}
 			{{if .HasError}}
 					 {{if .HasReturn}} // no params but return value/error
-							state := new({{.StateName}})
-							sdk.LoadState(ctx,state)
+							{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+							sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 							callResult, err = state.{{.FuncName}}({{if .HasContext}} ctx {{end}})
 					{{else}}	// no params/return value but return error
-							state := new({{.StateName}})
-							sdk.LoadState(ctx,state)
+							{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+							sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 							if err = state.{{.FuncName}}({{if .HasContext}} ctx {{end}}); err == nil {
 									callResult = = typegen.CborBool(true)
 								}
 					{{end}}
 			{{else}}
 					{{if .HasReturn}}	// no params no error but have return value
-						state := new({{.StateName}})
-						sdk.LoadState(ctx,state)
+						{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+						sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 						callResult = state.{{.FuncName}}({{if .HasContext}} ctx {{end}})
 					{{else}}		// no params/return value/error
-						state := new({{.StateName}})
-						sdk.LoadState(ctx,state)
+						{{if .NonReentrant}}sdk.EnterNonReentrant(ctx)
+nonReentrant = true
+{{end}}state := new({{.StateName}})
+						sdk.LoadState(ctx,state){{if and $.LazyFields (not .Readonly)}}
+lazyState = state{{end}}
 						state.{{.FuncName}}({{if .HasContext}} ctx {{end}})
 						callResult = = typegen.CborBool(true)
 					{{end}}
@@ -481,10 +559,28 @@ func Invoke(blockId uint32) uint32 {
 	}
 
 	if err != nil {
+		var actorErr *ferrors.ActorError
+		if errors.As(err, &actorErr) {
+			sdk.Exit(ctx, actorErr.ExitCode(), actorErr.Data, actorErr.Error())
+		}
 		exitCode := ferrors.USR_ILLEGAL_STATE
-		errors.As(err, &exitCode)
+		if errors.As(err, &exitCode) && exitCode.IsSystemError() {
+			// codes reserved for the VM, eg. returned by a failed send, can't be used to abort
+			exitCode = ferrors.USR_UNSPECIFIED
+		}
 		sdk.Abort(ctx, exitCode, fmt.Sprintf("call error %s", err))
 	}
+{{if .LazyFields}}
+	// save the collections loaded for modification, untouched ones keep their root
+	if lazyState != nil && ({{range $i, $f := .LazyFields}}{{if $i}} || {{end}}lazyState.{{$f}}.IsDirty(){{end}}) {
+		_ = sdk.SaveState(ctx, lazyState)
+	}
+{{end}}
+{{if .HasNonReentrant}}
+	if nonReentrant {
+		sdk.ExitNonReentrant(ctx)
+	}
+{{end}}
 
 	if !sdk.IsNil(callResult) {
 		buf := 	bytes.NewBuffer(nil)
@@ -0,0 +1,240 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+	"strings"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	typegen "github.com/whyrusleeping/cbor-gen"
+)
+
+// GenEvent generates MarshalEvent and UnmarshalEvent for the events declared by the Events method of the state,
+// so that contracts emit them by sdk.Emit and clients decode them by sdk.DecodeEvent without reflection, which
+// tinygo doesn't support. The output file goes next to the state, eg. ../contract/event_gen.go.
+//
+// Each exported field becomes an entry keyed by its name, the tag `event:"name,indexed"` renames the key of a
+// field and indexes it: "indexed" indexes key and value, "indexed_key" only the key and "indexed_value" only the
+// value. Fields tagged `event:"-"` are skipped. Fields are cbor marshalers, bools, integers, strings or byte slices.
+func GenEvent(stateT reflect.Type, output string) error {
+	if stateT.Kind() == reflect.Ptr {
+		stateT = stateT.Elem()
+	}
+	pkg := stateT.PkgPath()
+	eventTypes, err := getEvents(stateT)
+	if err != nil {
+		return err
+	}
+
+	imports := []typegen.Import{
+		{Name: "sdk", PkgPath: "github.com/ipfs-force-community/go-fvm-sdk/sdk"},
+		{Name: "sdkTypes", PkgPath: "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"},
+	}
+	var events []*eventCodeMeta
+	for _, evtT := range eventTypes {
+		evt := &eventCodeMeta{TypeName: evtT.Name(), EventName: evtT.Name()}
+		if namer, ok := reflect.New(evtT).Interface().(sdk.EventNamer); ok {
+			evt.EventName = namer.EventName()
+		}
+		for i := 0; i < evtT.NumField(); i++ {
+			field, ok, err := getEventField(pkg, evtT.Field(i))
+			if err != nil {
+				return fmt.Errorf("event %s: %w", evtT.Name(), err)
+			}
+			if !ok {
+				continue
+			}
+			imports = append(imports, ImportsForType(pkg, evtT.Field(i).Type)...)
+			evt.Fields = append(evt.Fields, field)
+		}
+		events = append(events, evt)
+	}
+
+	render, err := template.New("gen event").Funcs(funcs).Parse(eventTml)
+	if err != nil {
+		return err
+	}
+	buf := bytes.NewBuffer(nil)
+	err = render.Execute(buf, map[string]interface{}{
+		"PkgName": strings.Split(stateT.String(), ".")[0],
+		"Imports": dedupImports(imports),
+		"Events":  events,
+	})
+	if err != nil {
+		return err
+	}
+	return formateAndWriteCode(buf.Bytes(), output)
+}
+
+// getEvents returns the event types declared by the Events method of the state, if any.
+func getEvents(stateT reflect.Type) ([]reflect.Type, error) {
+	stateV := reflect.New(stateT)
+	eventsFunc, found := stateV.Type().MethodByName("Events")
+	if !found {
+		return nil, nil
+	}
+	returns := eventsFunc.Func.Call([]reflect.Value{stateV})
+	declared, ok := returns[0].Interface().([]interface{})
+	if !ok {
+		return nil, errors.New("assert Events return type fail")
+	}
+	var events []reflect.Type
+	for _, evt := range declared {
+		evtT := reflect.TypeOf(evt)
+		if evtT.Kind() == reflect.Ptr {
+			evtT = evtT.Elem()
+		}
+		if evtT.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("event %v must be struct", evtT)
+		}
+		events = append(events, evtT)
+	}
+	return events, nil
+}
+
+type eventCodeMeta struct {
+	TypeName  string
+	EventName string
+	Fields    []*eventFieldMeta
+}
+
+type eventFieldMeta struct {
+	Name string
+	Key  string
+	// Flags the flags of the entry, an expression on the sdk/types constants
+	Flags string
+	// Kind the EventWriter method writing the field, or Ptr for a pointer to a cbor marshaler
+	Kind string
+	// TypeName the field type, or its element type for a pointer
+	TypeName string
+}
+
+func getEventField(pkg string, f reflect.StructField) (*eventFieldMeta, bool, error) {
+	if f.PkgPath != "" {
+		return nil, false, nil
+	}
+	field := &eventFieldMeta{Name: f.Name, Key: f.Name, Flags: "0", TypeName: typeName(pkg, f.Type)}
+	if tag, ok := f.Tag.Lookup("event"); ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] == "-" {
+			return nil, false, nil
+		}
+		if len(parts[0]) > 0 {
+			field.Key = parts[0]
+		}
+		var indexKey, indexValue bool
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "indexed":
+				indexKey, indexValue = true, true
+			case "indexed_key":
+				indexKey = true
+			case "indexed_value":
+				indexValue = true
+			default:
+				return nil, false, fmt.Errorf("unknown event tag option %s of field %s", opt, f.Name)
+			}
+		}
+		switch {
+		case indexKey && indexValue:
+			field.Flags = "sdkTypes.FLAGINDEXEDALL"
+		case indexKey:
+			field.Flags = "sdkTypes.FLAGINDEXEDKEY"
+		case indexValue:
+			field.Flags = "sdkTypes.FLAGINDEXEDVALUE"
+		}
+	}
+
+	t := f.Type
+	switch {
+	case t.Kind() == reflect.Ptr && t.Implements(marshallerT) && t.Implements(unMarshallerT):
+		field.Kind = "Ptr"
+		field.TypeName = typeName(pkg, t.Elem())
+	case t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(marshallerT) && reflect.PtrTo(t).Implements(unMarshallerT):
+		field.Kind = "Value"
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			field.Kind = "Bool"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.Kind = "Int"
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.Kind = "Uint"
+		case reflect.String:
+			field.Kind = "String"
+		case reflect.Slice:
+			if t.Elem().Kind() == reflect.Uint8 {
+				field.Kind = "Bytes"
+			}
+		}
+	}
+	if len(field.Kind) == 0 {
+		return nil, false, fmt.Errorf("unsupported type %s of field %s", t, f.Name)
+	}
+	return field, true, nil
+}
+
+var eventTml = `// Code generated by github.com/ipfs-force-community/go-fvm-sdk. DO NOT EDIT.
+package {{.PkgName}}
+
+import (
+	{{range .Imports}}
+	 {{.Name}} "{{.PkgPath}}"
+	{{end}}
+)
+
+{{range .Events}}
+// MarshalEvent builds the entries of the {{.EventName}} event, see sdk.Emit.
+func (t *{{.TypeName}}) MarshalEvent() (*sdkTypes.ActorEvent, error) {
+	w := sdk.NewEventWriter("{{.EventName}}")
+	{{- range .Fields}}
+	{{- if eq .Kind "Ptr"}}
+	w.Value("{{.Key}}", {{.Flags|raw}}, t.{{.Name}})
+	{{- else if eq .Kind "Value"}}
+	w.Value("{{.Key}}", {{.Flags|raw}}, &t.{{.Name}})
+	{{- else if eq .Kind "Bool"}}
+	w.Bool("{{.Key}}", {{.Flags|raw}}, bool(t.{{.Name}}))
+	{{- else if eq .Kind "Int"}}
+	w.Int("{{.Key}}", {{.Flags|raw}}, int64(t.{{.Name}}))
+	{{- else if eq .Kind "Uint"}}
+	w.Uint("{{.Key}}", {{.Flags|raw}}, uint64(t.{{.Name}}))
+	{{- else if eq .Kind "String"}}
+	w.String("{{.Key}}", {{.Flags|raw}}, string(t.{{.Name}}))
+	{{- else if eq .Kind "Bytes"}}
+	w.Bytes("{{.Key}}", {{.Flags|raw}}, []byte(t.{{.Name}}))
+	{{- end}}
+	{{- end}}
+	return w.Event()
+}
+
+// UnmarshalEvent decodes the {{.EventName}} event built by MarshalEvent, returns sdk.ErrEventMismatch for an event
+// of another type. Entries unknown to {{.TypeName}} are ignored.
+func (t *{{.TypeName}}) UnmarshalEvent(evt sdkTypes.ActorEvent) error {
+	return sdk.ReadEvent(evt, "{{.EventName}}", func(key string, value []byte) error {
+		switch key {
+		{{- range .Fields}}
+		case "{{.Key}}":
+		{{- if eq .Kind "Ptr"}}
+			if sdk.IsEventNull(value) {
+				t.{{.Name}} = nil
+				return nil
+			}
+			t.{{.Name}} = new({{.TypeName|raw}})
+			return sdk.ReadEventValue(value, t.{{.Name}})
+		{{- else if eq .Kind "Value"}}
+			return sdk.ReadEventValue(value, &t.{{.Name}})
+		{{- else}}
+			v, err := sdk.ReadEvent{{.Kind}}(value)
+			t.{{.Name}} = {{.TypeName|raw}}(v)
+			return err
+		{{- end}}
+		{{- end}}
+		}
+		return nil
+	})
+}
+{{end}}
+`
@@ -0,0 +1,33 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/gen/testdata/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenEvent generates the event code and the client decoders of testdata/events, then runs the tests of its
+// client which round trip the events through them.
+func TestGenEvent(t *testing.T) {
+	stateT := reflect.TypeOf(events.Token{})
+	assert.Nil(t, GenEvent(stateT, "testdata/events/event_gen.go"))
+
+	meta, err := getEntryPackageMeta("client", stateT)
+	assert.Nil(t, err)
+	// the client header needs the venus api, only the event part of the client is generated
+	buf := bytes.NewBufferString("// Code generated by github.com/ipfs-force-community/go-fvm-sdk. DO NOT EDIT.\npackage client\n\nimport (\n")
+	for _, imp := range dedupImports(append(defaultClientImport, meta.Imports...)) {
+		fmt.Fprintf(buf, "%s %q\n", imp.Name, imp.PkgPath)
+	}
+	buf.WriteString(")\n")
+	assert.Nil(t, genClientEvents(buf, *meta))
+	assert.Nil(t, formateAndWriteCode(buf.Bytes(), "testdata/events/client/events_gen.go"))
+
+	out, err := exec.Command("go", "test", "./testdata/events/...").CombinedOutput()
+	assert.Nil(t, err, string(out))
+}
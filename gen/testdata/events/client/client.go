@@ -0,0 +1,16 @@
+// Package client stands in for the client generated by GenContractClient, the gen tests add its event decoders.
+package client
+
+import "github.com/filecoin-project/go-address"
+
+type ClientOption struct {
+	actor address.Address
+}
+
+type TokenClient struct {
+	cfg ClientOption
+}
+
+func NewTokenClient(actor address.Address) *TokenClient {
+	return &TokenClient{cfg: ClientOption{actor: actor}}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/go-fvm-sdk/gen/testdata/events"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventSource []*sdkTypes.EmittedEvent
+
+func (s eventSource) ActorEvents(_ context.Context, _ address.Address, _ abi.ChainEpoch) (<-chan *sdkTypes.EmittedEvent, error) {
+	out := make(chan *sdkTypes.EmittedEvent, len(s))
+	for _, evt := range s {
+		out <- evt
+	}
+	close(out)
+	return out, nil
+}
+
+func TestDecodeEvent(t *testing.T) {
+	to, _ := address.NewIDAddress(2)
+	transfer := events.Transfer{From: 1, To: &to, Amount: abi.NewTokenAmount(100), Memo: "hi", Delta: -3, Ok: true, Data: []byte{1}, Nonce: 7}
+	transferEvt, err := transfer.MarshalEvent()
+	assert.Nil(t, err)
+	assert.Len(t, transferEvt.Entries, 8)
+	assert.Equal(t, "memo", transferEvt.Entries[4].Key)
+	assert.Equal(t, sdkTypes.Flags(sdkTypes.FLAGINDEXEDKEY), transferEvt.Entries[4].Flags)
+	burn := events.Burn{Owner: 3, Amount: abi.NewTokenAmount(5)}
+	burnEvt, err := burn.MarshalEvent()
+	assert.Nil(t, err)
+
+	decoded, err := DecodeTransfer(*transferEvt)
+	assert.Nil(t, err)
+	assert.Equal(t, abi.ActorID(1), decoded.From)
+	assert.Equal(t, to, *decoded.To)
+	assert.Equal(t, "100", decoded.Amount.String())
+	assert.Equal(t, "hi", decoded.Memo)
+	assert.Equal(t, int64(-3), decoded.Delta)
+	assert.True(t, decoded.Ok)
+	assert.Equal(t, []byte{1}, decoded.Data)
+	assert.Equal(t, uint64(0), decoded.Nonce)
+
+	_, err = DecodeBurn(*transferEvt)
+	assert.True(t, errors.Is(err, sdk.ErrEventMismatch))
+	_, err = DecodeEvent(sdkTypes.ActorEvent{})
+	assert.True(t, errors.Is(err, sdk.ErrEventMismatch))
+
+	// a burn event whose amount isn't a big int
+	badBurn := *burnEvt
+	badBurn.Entries = append([]*sdkTypes.Entry{}, burnEvt.Entries...)
+	badAmount := *badBurn.Entries[2]
+	badAmount.Value = []byte{0x01}
+	badBurn.Entries[2] = &badAmount
+	_, err = DecodeEvent(badBurn)
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, sdk.ErrEventMismatch))
+
+	actor, _ := address.NewIDAddress(1000)
+	source := eventSource{
+		{Emitter: actor, Entries: transferEvt.Entries},
+		{Emitter: actor},
+		{Emitter: actor, Entries: badBurn.Entries},
+		{Emitter: actor, Entries: burnEvt.Entries},
+	}
+	sub, err := NewTokenClient(actor).SubscribeEvents(context.Background(), source, 0)
+	assert.Nil(t, err)
+	var got []*TokenEvent
+	for evt := range sub {
+		got = append(got, evt)
+	}
+	assert.Len(t, got, 3)
+	assert.Equal(t, "hi", got[0].Event.(*events.Transfer).Memo)
+	assert.Nil(t, got[1].Event)
+	assert.NotNil(t, got[1].Err)
+	assert.Nil(t, got[2].Err)
+	assert.Equal(t, abi.ActorID(3), got[2].Event.(*events.Burn).Owner)
+}
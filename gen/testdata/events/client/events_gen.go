@@ -0,0 +1,93 @@
+// Code generated by github.com/ipfs-force-community/go-fvm-sdk. DO NOT EDIT.
+package client
+
+import (
+	context "context"
+	"errors"
+	"fmt"
+
+	address "github.com/filecoin-project/go-address"
+	abi "github.com/filecoin-project/go-state-types/abi"
+	events "github.com/ipfs-force-community/go-fvm-sdk/gen/testdata/events"
+	sdk "github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+)
+
+// EventSource streams the events emitted by an actor from height fromHeight. The venus node of the client has no
+// api returning actor events, its message receipts don't carry them, so the caller supplies the source, eg. an
+// adapter over a node serving the events of FIP-0049 filtered on the emitter address from fromHeight.
+type EventSource interface {
+	ActorEvents(ctx context.Context, emitter address.Address, fromHeight abi.ChainEpoch) (<-chan *sdkTypes.EmittedEvent, error)
+}
+
+// TokenEvent an event of the actor decoded into one of its declared event types.
+type TokenEvent struct {
+	*sdkTypes.EmittedEvent
+	// Event the decoded event, eg. *events.Transfer, nil if Err is set
+	Event interface{}
+	// Err the error decoding an event of a declared type, eg. a malformed entry
+	Err error
+}
+
+// DecodeTransfer decodes a Transfer event of the actor, eg. from a message receipt.
+func DecodeTransfer(evt sdkTypes.ActorEvent) (*events.Transfer, error) {
+	out, err := sdk.DecodeEvent[events.Transfer](evt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecodeBurn decodes a Burn event of the actor, eg. from a message receipt.
+func DecodeBurn(evt sdkTypes.ActorEvent) (*events.Burn, error) {
+	out, err := sdk.DecodeEvent[events.Burn](evt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecodeEvent decodes an event of the actor into one of its declared event types, returns sdk.ErrEventMismatch
+// for other events.
+func DecodeEvent(evt sdkTypes.ActorEvent) (interface{}, error) {
+	if out, err := DecodeTransfer(evt); err == nil {
+		return out, nil
+	} else if !errors.Is(err, sdk.ErrEventMismatch) {
+		return nil, err
+	}
+	if out, err := DecodeBurn(evt); err == nil {
+		return out, nil
+	} else if !errors.Is(err, sdk.ErrEventMismatch) {
+		return nil, err
+	}
+	return nil, sdk.ErrEventMismatch
+}
+
+// SubscribeEvents streams the decoded events of the actor from height fromHeight, events which aren't of a
+// declared event type are skipped. An event of a declared type which fails to decode is streamed with Err set.
+func (c *TokenClient) SubscribeEvents(ctx context.Context, source EventSource, fromHeight abi.ChainEpoch) (<-chan *TokenEvent, error) {
+	if c.cfg.actor == address.Undef {
+		return nil, fmt.Errorf("unset actor address for subscribe")
+	}
+	raw, err := source.ActorEvents(ctx, c.cfg.actor, fromHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe actor events: %w", err)
+	}
+
+	out := make(chan *TokenEvent)
+	go func() {
+		defer close(out)
+		for evt := range raw {
+			decoded, err := DecodeEvent(sdkTypes.ActorEvent{Entries: evt.Entries})
+			if errors.Is(err, sdk.ErrEventMismatch) {
+				continue
+			}
+			select {
+			case out <- &TokenEvent{EmittedEvent: evt, Event: decoded, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,88 @@
+// Code generated by github.com/ipfs-force-community/go-fvm-sdk. DO NOT EDIT.
+package events
+
+import (
+	address "github.com/filecoin-project/go-address"
+
+	abi "github.com/filecoin-project/go-state-types/abi"
+
+	sdk "github.com/ipfs-force-community/go-fvm-sdk/sdk"
+
+	sdkTypes "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+)
+
+// MarshalEvent builds the entries of the Transfer event, see sdk.Emit.
+func (t *Transfer) MarshalEvent() (*sdkTypes.ActorEvent, error) {
+	w := sdk.NewEventWriter("Transfer")
+	w.Uint("from", sdkTypes.FLAGINDEXEDALL, uint64(t.From))
+	w.Value("to", sdkTypes.FLAGINDEXEDVALUE, t.To)
+	w.Value("amount", 0, &t.Amount)
+	w.String("memo", sdkTypes.FLAGINDEXEDKEY, string(t.Memo))
+	w.Int("Delta", 0, int64(t.Delta))
+	w.Bool("Ok", 0, bool(t.Ok))
+	w.Bytes("Data", 0, []byte(t.Data))
+	return w.Event()
+}
+
+// UnmarshalEvent decodes the Transfer event built by MarshalEvent, returns sdk.ErrEventMismatch for an event
+// of another type. Entries unknown to Transfer are ignored.
+func (t *Transfer) UnmarshalEvent(evt sdkTypes.ActorEvent) error {
+	return sdk.ReadEvent(evt, "Transfer", func(key string, value []byte) error {
+		switch key {
+		case "from":
+			v, err := sdk.ReadEventUint(value)
+			t.From = abi.ActorID(v)
+			return err
+		case "to":
+			if sdk.IsEventNull(value) {
+				t.To = nil
+				return nil
+			}
+			t.To = new(address.Address)
+			return sdk.ReadEventValue(value, t.To)
+		case "amount":
+			return sdk.ReadEventValue(value, &t.Amount)
+		case "memo":
+			v, err := sdk.ReadEventString(value)
+			t.Memo = string(v)
+			return err
+		case "Delta":
+			v, err := sdk.ReadEventInt(value)
+			t.Delta = int64(v)
+			return err
+		case "Ok":
+			v, err := sdk.ReadEventBool(value)
+			t.Ok = bool(v)
+			return err
+		case "Data":
+			v, err := sdk.ReadEventBytes(value)
+			t.Data = []uint8(v)
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalEvent builds the entries of the burn event, see sdk.Emit.
+func (t *Burn) MarshalEvent() (*sdkTypes.ActorEvent, error) {
+	w := sdk.NewEventWriter("burn")
+	w.Uint("Owner", 0, uint64(t.Owner))
+	w.Value("Amount", 0, &t.Amount)
+	return w.Event()
+}
+
+// UnmarshalEvent decodes the burn event built by MarshalEvent, returns sdk.ErrEventMismatch for an event
+// of another type. Entries unknown to Burn are ignored.
+func (t *Burn) UnmarshalEvent(evt sdkTypes.ActorEvent) error {
+	return sdk.ReadEvent(evt, "burn", func(key string, value []byte) error {
+		switch key {
+		case "Owner":
+			v, err := sdk.ReadEventUint(value)
+			t.Owner = abi.ActorID(v)
+			return err
+		case "Amount":
+			return sdk.ReadEventValue(value, &t.Amount)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+// Package events is a contract declaring events, the gen tests generate its event code and client decoders.
+package events
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+)
+
+type Token struct{}
+
+func (t *Token) Export() []interface{} {
+	return nil
+}
+
+func (t *Token) Events() []interface{} {
+	return []interface{}{
+		Transfer{},
+		Burn{},
+	}
+}
+
+type Transfer struct {
+	From   abi.ActorID      `event:"from,indexed"`
+	To     *address.Address `event:"to,indexed_value"`
+	Amount abi.TokenAmount  `event:"amount"`
+	Memo   string           `event:"memo,indexed_key"`
+	Delta  int64
+	Ok     bool
+	Data   []byte
+	Nonce  uint64 `event:"-"`
+}
+
+type Burn struct {
+	Owner  abi.ActorID
+	Amount abi.TokenAmount
+}
+
+func (Burn) EventName() string {
+	return "burn"
+}
@@ -23,7 +23,8 @@ func GetActorCodeCid(ctx context.Context, addr address.Address) (cid.Cid, error)
 	return sys.GetActorCodeCid(ctx, addr)
 }
 
-// LookupDelegatedAddress look up the address at an actor  ID. Returns `None` if the actor cannot be found.
+// LookupDelegatedAddress look up the delegated (f4) address of an actor ID. Returns address.Undef if the actor has
+// no delegated address and a NotFound error if the actor cannot be found.
 func LookupDelegatedAddress(ctx context.Context, actorID abi.ActorID) (address.Address, error) {
 	return sys.LookupDelegatedAddress(ctx, actorID)
 }
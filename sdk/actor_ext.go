@@ -1,16 +1,20 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
+	"github.com/ipfs/go-cid"
 )
 
 // IsAccountAddress use check whether specific address is  action type
@@ -49,6 +53,55 @@ func InitializeAccount(ctx context.Context, addr address.Address) (abi.ActorID,
 	return sys.ResolveAddress(ctx, addr)
 }
 
+// Exec creates an actor running the code `codeCid` through the Init actor, which calls its constructor with
+// `constructorParams`, nil for no params. The code must be installed, see InstallParams.
+func Exec(ctx context.Context, codeCid cid.Cid, constructorParams cbor.Marshaler, opts ...SendOption) (*types.ExecReturn, error) {
+	params, err := marshalConstructorParams(constructorParams)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := Call[*types.ExecParams, types.ExecReturn](ctx, initActorAddress, types.MethodExec, &types.ExecParams{
+		CodeCID:           codeCid,
+		ConstructorParams: params,
+	}, big.Zero(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// Exec4 is Exec also assigning the f4 address `subAddress` in the namespace of the calling actor to the new actor.
+// The Init actor only accepts Exec4 from the Ethereum address manager (actor 10), any other caller gets a
+// *CallError with exit code USR_FORBIDDEN, so user actors can't use it.
+func Exec4(ctx context.Context, codeCid cid.Cid, constructorParams cbor.Marshaler, subAddress []byte, opts ...SendOption) (*types.Exec4Return, error) {
+	params, err := marshalConstructorParams(constructorParams)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := Call[*types.Exec4Params, types.Exec4Return](ctx, initActorAddress, types.MethodExec4, &types.Exec4Params{
+		CodeCID:           codeCid,
+		ConstructorParams: params,
+		SubAddress:        subAddress,
+	}, big.Zero(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+var initActorAddress, _ = address.NewIDAddress(uint64(types.InitActorID))
+
+func marshalConstructorParams(constructorParams cbor.Marshaler) ([]byte, error) {
+	if IsNil(constructorParams) {
+		return nil, nil
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := constructorParams.MarshalCBOR(buf); err != nil {
+		return nil, fmt.Errorf("failed to marshal constructor params: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 // SameAddress check if two address is the same actor
 func SameAddress(ctx context.Context, addrA, addrB address.Address) bool {
 	protocolA := addrA.Protocol()
@@ -0,0 +1,63 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec(t *testing.T) {
+	sim, ctx := simulated.CreateSimulateEnv(&types.MessageContext{Receiver: 1000}, &types.NetworkContext{}, big.Zero())
+	code, err := abi.CidBuilder.Sum([]byte("vault"))
+	assert.Nil(t, err)
+
+	owner := types.CborString("alice")
+	ret, err := Exec(ctx, code, &owner)
+	assert.Nil(t, err)
+	id, err := address.IDFromAddress(ret.IDAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), id)
+	robustID, err := ResolveAddress(ctx, ret.RobustAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, abi.ActorID(100), robustID)
+	actorCode, err := GetActorCodeCid(ctx, ret.IDAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, code, actorCode)
+
+	sent := sim.SentMessages()
+	assert.Equal(t, types.MethodExec, sent[0].Method)
+	params := types.ExecParams{}
+	assert.Nil(t, params.UnmarshalCBOR(bytes.NewReader(sent[0].Params)))
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, owner.MarshalCBOR(buf))
+	assert.Equal(t, buf.Bytes(), params.ConstructorParams)
+
+	// only the Ethereum address manager may call Exec4
+	_, err = Exec4(ctx, code, nil, []byte("bob"))
+	var callErr *CallError
+	assert.True(t, errors.As(err, &callErr))
+	assert.Equal(t, ferrors.USR_FORBIDDEN, callErr.Code)
+
+	_, ctx = simulated.CreateSimulateEnv(&types.MessageContext{Receiver: abi.ActorID(types.EthereumAddressManagerActorID)}, &types.NetworkContext{}, big.Zero())
+	ret4, err := Exec4(ctx, code, nil, []byte("bob"))
+	assert.Nil(t, err)
+	f4, err := address.NewDelegatedAddress(types.EthereumAddressManagerActorID, []byte("bob"))
+	assert.Nil(t, err)
+	f4ID, err := ResolveAddress(ctx, f4)
+	assert.Nil(t, err)
+	assert.Equal(t, abi.ActorID(100), f4ID)
+	id4, err := address.IDFromAddress(ret4.IDAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(f4ID), id4)
+}
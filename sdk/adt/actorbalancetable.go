@@ -0,0 +1,161 @@
+package adt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+)
+
+var (
+	// ErrInsufficientBalance returned when a debit or transfer would take a balance below its floor.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrNegativeAmount returned when a negative amount is credited, debited or transferred.
+	ErrNegativeAmount = errors.New("negative amount")
+)
+
+// ActorBalanceTable a map of actor ids to (positive) token amounts, for token actors keeping balances by
+// actor id. Absent keys implicitly have a balance of zero, and zero balances are removed from the map.
+// The table also keeps the sum of all balances, usually the total supply of the token.
+type ActorBalanceTable struct {
+	m     *Map
+	total abi.TokenAmount
+}
+
+// AsActorBalanceTable interprets a store as actor balance table with root `r`, `total` must be the sum of
+// all balances in the table, usually the total supply saved in actor state next to the root.
+func AsActorBalanceTable(s Store, r cid.Cid, total abi.TokenAmount) (*ActorBalanceTable, error) {
+	m, err := AsMap(s, r, BalanceTableBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &ActorBalanceTable{m: m, total: total}, nil
+}
+
+// MakeEmptyActorBalanceTable creates a new actor balance table with zero total.
+func MakeEmptyActorBalanceTable(s Store) (*ActorBalanceTable, error) {
+	m, err := MakeEmptyMap(s, BalanceTableBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &ActorBalanceTable{m: m, total: big.Zero()}, nil
+}
+
+// Root returns the root cid of underlying HAMT.
+func (t *ActorBalanceTable) Root() (cid.Cid, error) {
+	return t.m.Root()
+}
+
+// Total returns the sum of all balances.
+func (t *ActorBalanceTable) Total() abi.TokenAmount {
+	return t.total
+}
+
+// Get gets the balance of an actor, which is zero if the actor has never been credited.
+func (t *ActorBalanceTable) Get(id abi.ActorID) (abi.TokenAmount, error) {
+	var value abi.TokenAmount
+	found, err := t.m.Get(types.ActorKey(id), &value)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if !found {
+		return big.Zero(), nil
+	}
+	return value, nil
+}
+
+// Credit adds `amount` to the balance of an actor and to the total, returns the new balance.
+func (t *ActorBalanceTable) Credit(id abi.ActorID, amount abi.TokenAmount) (abi.TokenAmount, error) {
+	if amount.Sign() < 0 {
+		return big.Zero(), fmt.Errorf("%w: credit %v to %v", ErrNegativeAmount, amount, id)
+	}
+	prev, err := t.Get(id)
+	if err != nil {
+		return big.Zero(), err
+	}
+	balance := big.Add(prev, amount)
+	if err := t.set(id, balance); err != nil {
+		return big.Zero(), err
+	}
+	t.total = big.Add(t.total, amount)
+	return balance, nil
+}
+
+// Debit subtracts `amount` from the balance of an actor and from the total, requiring the resulting
+// balance to be at least `floor`. Returns the new balance.
+func (t *ActorBalanceTable) Debit(id abi.ActorID, amount abi.TokenAmount, floor abi.TokenAmount) (abi.TokenAmount, error) {
+	if amount.Sign() < 0 {
+		return big.Zero(), fmt.Errorf("%w: debit %v from %v", ErrNegativeAmount, amount, id)
+	}
+	prev, err := t.Get(id)
+	if err != nil {
+		return big.Zero(), err
+	}
+	balance := big.Sub(prev, amount)
+	if balance.LessThan(floor) || balance.Sign() < 0 {
+		return big.Zero(), fmt.Errorf("%w: debit %v from %v with balance %v and floor %v", ErrInsufficientBalance, amount, id, prev, floor)
+	}
+	if err := t.set(id, balance); err != nil {
+		return big.Zero(), err
+	}
+	t.total = big.Sub(t.total, amount)
+	return balance, nil
+}
+
+// Transfer moves `amount` from the balance of `from` to the balance of `to`, the total doesn't change.
+// Returns the new balances of both actors.
+func (t *ActorBalanceTable) Transfer(from, to abi.ActorID, amount abi.TokenAmount) (abi.TokenAmount, abi.TokenAmount, error) {
+	if amount.Sign() < 0 {
+		return big.Zero(), big.Zero(), fmt.Errorf("%w: transfer %v from %v to %v", ErrNegativeAmount, amount, from, to)
+	}
+	fromBalance, err := t.Get(from)
+	if err != nil {
+		return big.Zero(), big.Zero(), err
+	}
+	if fromBalance.LessThan(amount) {
+		return big.Zero(), big.Zero(), fmt.Errorf("%w: transfer %v from %v with balance %v", ErrInsufficientBalance, amount, from, fromBalance)
+	}
+	if from == to || amount.IsZero() {
+		toBalance, err := t.Get(to)
+		return fromBalance, toBalance, err
+	}
+
+	fromBalance = big.Sub(fromBalance, amount)
+	if err := t.set(from, fromBalance); err != nil {
+		return big.Zero(), big.Zero(), err
+	}
+	toBalance, err := t.Get(to)
+	if err != nil {
+		return big.Zero(), big.Zero(), err
+	}
+	toBalance = big.Add(toBalance, amount)
+	if err := t.set(to, toBalance); err != nil {
+		return big.Zero(), big.Zero(), err
+	}
+	return fromBalance, toBalance, nil
+}
+
+// ForEach iterates all non-zero balances.
+// Iteration halts if the function returns an error.
+func (t *ActorBalanceTable) ForEach(fn func(id abi.ActorID, balance abi.TokenAmount) error) error {
+	var balance abi.TokenAmount
+	return t.m.ForEach(&balance, func(key string) error {
+		id, err := types.ParseActorKey(key)
+		if err != nil {
+			return err
+		}
+		return fn(abi.ActorID(id), balance)
+	})
+}
+
+// set stores the balance, removing the entry if it's zero.
+func (t *ActorBalanceTable) set(id abi.ActorID, balance abi.TokenAmount) error {
+	if balance.IsZero() {
+		_, err := t.m.TryDelete(types.ActorKey(id))
+		return err
+	}
+	return t.m.Put(types.ActorKey(id), &balance)
+}
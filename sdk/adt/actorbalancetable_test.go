@@ -0,0 +1,56 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorBalanceTable(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	table, err := MakeEmptyActorBalanceTable(store)
+	assert.Nil(t, err)
+
+	_, err = table.Credit(1, big.NewInt(100))
+	assert.Nil(t, err)
+	_, err = table.Credit(2, big.NewInt(50))
+	assert.Nil(t, err)
+	_, err = table.Credit(1, big.NewInt(-1))
+	assert.ErrorIs(t, err, ErrNegativeAmount)
+
+	fromBalance, toBalance, err := table.Transfer(1, 2, big.NewInt(30))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(70), fromBalance)
+	assert.Equal(t, big.NewInt(80), toBalance)
+	_, _, err = table.Transfer(1, 2, big.NewInt(71))
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+	_, err = table.Debit(2, big.NewInt(50), big.NewInt(40))
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	balance, err := table.Debit(2, big.NewInt(80), big.Zero())
+	assert.Nil(t, err)
+	assert.True(t, balance.IsZero())
+	assert.Equal(t, big.NewInt(70), table.Total())
+
+	root, err := table.Root()
+	assert.Nil(t, err)
+	loaded, err := AsActorBalanceTable(store, root, table.Total())
+	assert.Nil(t, err)
+
+	ids := map[abi.ActorID]abi.TokenAmount{}
+	assert.Nil(t, loaded.ForEach(func(id abi.ActorID, balance abi.TokenAmount) error {
+		ids[id] = balance
+		return nil
+	}))
+	// zero balance of actor 2 was pruned
+	assert.Equal(t, map[abi.ActorID]abi.TokenAmount{1: big.NewInt(70)}, ids)
+	assert.Nil(t, CheckBalanceTable(store, root).Err())
+}
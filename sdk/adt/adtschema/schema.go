@@ -0,0 +1,46 @@
+// Package adtschema builds the schema of a state by reflection, for tools and tests reporting state storage
+// with adt.WalkState. It's not meant for actor code, TinyGo doesn't support the reflection it uses.
+package adtschema
+
+import (
+	"reflect"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/adt"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+var (
+	cidType     = reflect.TypeOf(cid.Cid{})
+	cborCidType = reflect.TypeOf(cbg.CborCid{})
+	lazyType    = reflect.TypeOf((*adt.LazyField)(nil)).Elem()
+)
+
+// Of builds the schema of a state struct encoded as cbor tuple by cbor-gen, naming the cid and lazy fields
+// after the struct fields.
+func Of(state interface{}) *adt.StateSchema {
+	schema := &adt.StateSchema{Fields: make(map[int]string)}
+	t := reflect.TypeOf(state)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return schema
+	}
+	index := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft == cidType || ft == cborCidType || reflect.PtrTo(ft).Implements(lazyType) {
+			schema.Fields[index] = f.Name
+		}
+		index++
+	}
+	return schema
+}
@@ -0,0 +1,25 @@
+package adtschema
+
+import (
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/adt"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+type testState struct {
+	Balances cid.Cid
+	Count    uint64
+	Log      *cbg.CborCid
+	hidden   cid.Cid //nolint
+	Lazy     adt.LazyMap
+}
+
+func TestOf(t *testing.T) {
+	schema := Of(&testState{})
+	assert.Equal(t, map[int]string{0: "Balances", 2: "Log", 3: "Lazy"}, schema.Fields)
+
+	assert.Empty(t, Of(uint64(0)).Fields)
+}
@@ -26,8 +26,10 @@ func AsBalanceTable(s Store, r cid.Cid) (*BalanceTable, error) {
 	}
 
 	return &BalanceTable{
-		root:  m.root,
-		store: s,
+		lastCid:  m.lastCid,
+		root:     m.root,
+		store:    s,
+		bitwidth: m.bitwidth,
 	}, nil
 }
 
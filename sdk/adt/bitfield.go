@@ -0,0 +1,440 @@
+package adt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// MaxBitFieldEncodedSize is the maximum encoded size of a bitfield, same as go-bitfield.
+const MaxBitFieldEncodedSize = 32 << 10
+
+// rlePlusVersion the only RLE+ version defined by the filecoin spec.
+const rlePlusVersion = 0
+
+var (
+	// ErrBitFieldDecode returned when the RLE+ data is malformed or not minimally encoded.
+	ErrBitFieldDecode = errors.New("invalid RLE+ encoding")
+	// ErrBitFieldTooLarge returned when the encoded bitfield exceeds MaxBitFieldEncodedSize.
+	ErrBitFieldTooLarge = errors.New("encoded bitfield too large")
+)
+
+// BitRun a run of `Len` consecutive bits with value `Val`.
+type BitRun struct {
+	Val bool
+	Len uint64
+}
+
+// bitRange a range of set bits [start, end).
+type bitRange struct {
+	start uint64
+	end   uint64
+}
+
+// BitField a set of uint64 stored as RLE+, the CBOR encoding is compatible with
+// https://github.com/filecoin-project/go-bitfield, so it can be used as a field of cbor-gen state struct.
+// Unlike go-bitfield, it keeps the decoded ranges instead of the lazy run iterators and doesn't use
+// reflect, so it can be built with TinyGo.
+type BitField struct {
+	// sorted, non-overlapping and non-adjacent
+	ranges []bitRange
+}
+
+// NewBitField creates an empty bitfield.
+func NewBitField() BitField {
+	return BitField{}
+}
+
+// NewBitFieldFromSet creates a bitfield with all the bits in `set` set.
+func NewBitFieldFromSet(set []uint64) BitField {
+	bits := make([]uint64, len(set))
+	copy(bits, set)
+	sort.Slice(bits, func(i, j int) bool { return bits[i] < bits[j] })
+
+	var bf BitField
+	for _, bit := range bits {
+		bf.appendRange(bitRange{start: bit, end: bit + 1})
+	}
+	return bf
+}
+
+// NewBitFieldFromRuns creates a bitfield from runs, the first run starts at bit 0.
+func NewBitFieldFromRuns(runs []BitRun) (BitField, error) {
+	var bf BitField
+	var pos uint64
+	for _, run := range runs {
+		if run.Len > math.MaxUint64-pos {
+			return BitField{}, fmt.Errorf("bitfield runs overflow uint64")
+		}
+		if run.Val && run.Len > 0 {
+			bf.appendRange(bitRange{start: pos, end: pos + run.Len})
+		}
+		pos += run.Len
+	}
+	return bf, nil
+}
+
+// NewBitFieldFromBytes decodes RLE+ encoded bytes.
+func NewBitFieldFromBytes(buf []byte) (BitField, error) {
+	runs, err := decodeRLEPlus(buf)
+	if err != nil {
+		return BitField{}, err
+	}
+	return NewBitFieldFromRuns(runs)
+}
+
+// Set sets the bit.
+func (bf *BitField) Set(bit uint64) {
+	*bf = bf.Union(BitField{ranges: []bitRange{{start: bit, end: bit + 1}}})
+}
+
+// Unset clears the bit.
+func (bf *BitField) Unset(bit uint64) {
+	*bf = bf.Subtract(BitField{ranges: []bitRange{{start: bit, end: bit + 1}}})
+}
+
+// IsSet checks whether the bit is set.
+func (bf BitField) IsSet(bit uint64) bool {
+	i := sort.Search(len(bf.ranges), func(i int) bool { return bf.ranges[i].end > bit })
+	return i < len(bf.ranges) && bf.ranges[i].start <= bit
+}
+
+// Count returns the number of set bits.
+func (bf BitField) Count() uint64 {
+	var count uint64
+	for _, r := range bf.ranges {
+		count += r.end - r.start
+	}
+	return count
+}
+
+// IsEmpty checks whether there is no bit set.
+func (bf BitField) IsEmpty() bool {
+	return len(bf.ranges) == 0
+}
+
+// First returns the lowest set bit, the bool is false if the bitfield is empty.
+func (bf BitField) First() (uint64, bool) {
+	if len(bf.ranges) == 0 {
+		return 0, false
+	}
+	return bf.ranges[0].start, true
+}
+
+// Last returns the highest set bit, the bool is false if the bitfield is empty.
+func (bf BitField) Last() (uint64, bool) {
+	if len(bf.ranges) == 0 {
+		return 0, false
+	}
+	return bf.ranges[len(bf.ranges)-1].end - 1, true
+}
+
+// Union returns a new bitfield with the bits set in either `bf` or `other`.
+func (bf BitField) Union(other BitField) BitField {
+	var out BitField
+	i, j := 0, 0
+	for i < len(bf.ranges) || j < len(other.ranges) {
+		if j == len(other.ranges) || (i < len(bf.ranges) && bf.ranges[i].start <= other.ranges[j].start) {
+			out.appendRange(bf.ranges[i])
+			i++
+		} else {
+			out.appendRange(other.ranges[j])
+			j++
+		}
+	}
+	return out
+}
+
+// Intersect returns a new bitfield with the bits set in both `bf` and `other`.
+func (bf BitField) Intersect(other BitField) BitField {
+	var out BitField
+	i, j := 0, 0
+	for i < len(bf.ranges) && j < len(other.ranges) {
+		a, b := bf.ranges[i], other.ranges[j]
+		start, end := maxUint64(a.start, b.start), minUint64(a.end, b.end)
+		if start < end {
+			out.appendRange(bitRange{start: start, end: end})
+		}
+		if a.end < b.end {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// Subtract returns a new bitfield with the bits set in `bf` but not in `other`.
+func (bf BitField) Subtract(other BitField) BitField {
+	var out BitField
+	j := 0
+	for _, r := range bf.ranges {
+		start := r.start
+		for j < len(other.ranges) && other.ranges[j].end <= start {
+			j++
+		}
+		for k := j; k < len(other.ranges) && other.ranges[k].start < r.end; k++ {
+			if other.ranges[k].start > start {
+				out.appendRange(bitRange{start: start, end: other.ranges[k].start})
+			}
+			start = maxUint64(start, other.ranges[k].end)
+		}
+		if start < r.end {
+			out.appendRange(bitRange{start: start, end: r.end})
+		}
+	}
+	return out
+}
+
+// ForEach calls `fn` for every set bit in ascending order.
+// Iteration halts if the function returns an error.
+func (bf BitField) ForEach(fn func(bit uint64) error) error {
+	for _, r := range bf.ranges {
+		for bit := r.start; bit < r.end; bit++ {
+			if err := fn(bit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ForEachRun calls `fn` for every run starting from bit 0, runs alternate between unset and set bits,
+// the first run may be a set run and the trailing unset run is omitted.
+// Iteration halts if the function returns an error.
+func (bf BitField) ForEachRun(fn func(run BitRun) error) error {
+	var pos uint64
+	for _, r := range bf.ranges {
+		if r.start > pos {
+			if err := fn(BitRun{Val: false, Len: r.start - pos}); err != nil {
+				return err
+			}
+		}
+		if err := fn(BitRun{Val: true, Len: r.end - r.start}); err != nil {
+			return err
+		}
+		pos = r.end
+	}
+	return nil
+}
+
+// All returns all the set bits, fails if there are more than `max` of them.
+func (bf BitField) All(max uint64) ([]uint64, error) {
+	count := bf.Count()
+	if count > max {
+		return nil, fmt.Errorf("expected %d bits or less, bitfield has %d", max, count)
+	}
+	out := make([]uint64, 0, count)
+	_ = bf.ForEach(func(bit uint64) error {
+		out = append(out, bit)
+		return nil
+	})
+	return out, nil
+}
+
+// Bytes returns the RLE+ encoding of the bitfield.
+func (bf BitField) Bytes() []byte {
+	w := bitWriter{}
+	w.put(rlePlusVersion, 2)
+	first := true
+	var varBuf [binary.MaxVarintLen64]byte
+	_ = bf.ForEachRun(func(run BitRun) error {
+		if first {
+			if run.Val {
+				w.put(1, 1)
+			} else {
+				w.put(0, 1)
+			}
+			first = false
+		}
+		switch {
+		case run.Len == 1:
+			w.put(1, 1)
+		case run.Len < 16:
+			w.put(2, 2)
+			w.put(byte(run.Len), 4)
+		default:
+			w.put(0, 2)
+			n := binary.PutUvarint(varBuf[:], run.Len)
+			for i := 0; i < n; i++ {
+				w.put(varBuf[i], 8)
+			}
+		}
+		return nil
+	})
+	return w.out()
+}
+
+// MarshalCBOR encodes the bitfield as a CBOR byte string of RLE+ data.
+func (bf BitField) MarshalCBOR(w io.Writer) error {
+	rle := bf.Bytes()
+	if len(rle) > MaxBitFieldEncodedSize {
+		return fmt.Errorf("%w: %d bytes", ErrBitFieldTooLarge, len(rle))
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajByteString, uint64(len(rle))); err != nil {
+		return err
+	}
+	_, err := w.Write(rle)
+	return err
+}
+
+// UnmarshalCBOR decodes the bitfield from a CBOR byte string of RLE+ data.
+func (bf *BitField) UnmarshalCBOR(r io.Reader) error {
+	maj, extra, err := cbg.CborReadHeader(r)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+	if extra > MaxBitFieldEncodedSize {
+		return fmt.Errorf("%w: %d bytes", ErrBitFieldTooLarge, extra)
+	}
+	buf := make([]byte, extra)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	decoded, err := NewBitFieldFromBytes(buf)
+	if err != nil {
+		return err
+	}
+	*bf = decoded
+	return nil
+}
+
+// appendRange appends a range that starts at or after the start of the last range, merging overlapping
+// and adjacent ranges.
+func (bf *BitField) appendRange(r bitRange) {
+	if r.start >= r.end {
+		return
+	}
+	if n := len(bf.ranges); n > 0 && r.start <= bf.ranges[n-1].end {
+		bf.ranges[n-1].end = maxUint64(bf.ranges[n-1].end, r.end)
+		return
+	}
+	bf.ranges = append(bf.ranges, r)
+}
+
+func decodeRLEPlus(buf []byte) ([]BitRun, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	if buf[len(buf)-1] == 0 {
+		return nil, fmt.Errorf("%w: not minimally encoded", ErrBitFieldDecode)
+	}
+
+	r := bitReader{buf: buf}
+	if r.get(2) != rlePlusVersion {
+		return nil, fmt.Errorf("%w: wrong version", ErrBitFieldDecode)
+	}
+	val := r.get(1) == 1
+
+	var runs []BitRun
+	for {
+		var length uint64
+		if r.get(1) == 1 {
+			length = 1
+		} else if r.get(1) == 1 {
+			length = uint64(r.get(4))
+		} else {
+			var err error
+			if length, err = r.getVarint(); err != nil {
+				return nil, err
+			}
+		}
+		// zero length run is the padding at the end of stream
+		if length == 0 {
+			break
+		}
+		runs = append(runs, BitRun{Val: val, Len: length})
+		val = !val
+	}
+	return runs, nil
+}
+
+// bitWriter writes bits starting from the least significant bit of each byte.
+type bitWriter struct {
+	buf  []byte
+	bits uint16
+	cap  byte
+}
+
+func (w *bitWriter) put(val byte, count byte) {
+	w.bits |= uint16(val) << w.cap
+	w.cap += count
+	if w.cap >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.cap -= 8
+		w.bits >>= 8
+	}
+}
+
+// out returns the written bytes with trailing zero bytes removed.
+func (w *bitWriter) out() []byte {
+	if w.cap > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+	}
+	for len(w.buf) > 0 && w.buf[len(w.buf)-1] == 0 {
+		w.buf = w.buf[:len(w.buf)-1]
+	}
+	return w.buf
+}
+
+// bitReader reads bits starting from the least significant bit of each byte,
+// reading past the end returns zero bits.
+type bitReader struct {
+	buf []byte
+	pos uint64
+}
+
+func (r *bitReader) get(count byte) byte {
+	var res byte
+	for i := byte(0); i < count; i++ {
+		idx := r.pos / 8
+		if idx < uint64(len(r.buf)) && r.buf[idx]&(1<<(r.pos%8)) != 0 {
+			res |= 1 << i
+		}
+		r.pos++
+	}
+	return res
+}
+
+func (r *bitReader) getVarint() (uint64, error) {
+	var x uint64
+	var s uint
+	for i := 0; ; i++ {
+		if i == binary.MaxVarintLen64 {
+			return 0, fmt.Errorf("%w: run too long", ErrBitFieldDecode)
+		}
+		b := r.get(8)
+		if b < 0x80 {
+			if i == binary.MaxVarintLen64-1 && b > 1 {
+				return 0, fmt.Errorf("%w: run too long", ErrBitFieldDecode)
+			} else if b == 0 && s > 0 {
+				return 0, fmt.Errorf("%w: invalid run", ErrBitFieldDecode)
+			}
+			return x | uint64(b)<<s, nil
+		}
+		x |= uint64(b&0x7f) << s
+		s += 7
+	}
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,68 @@
+package adt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitFieldEncoding(t *testing.T) {
+	// expected bytes are produced by github.com/filecoin-project/go-bitfield
+	cases := []struct {
+		set    []uint64
+		expect []byte
+	}{
+		{set: nil, expect: []byte{0x40}},
+		{set: []uint64{0}, expect: []byte{0x41, 0x0c}},
+		{set: []uint64{0, 1, 2, 8, 9}, expect: []byte{0x43, 0x74, 0x2c, 0x05}},
+		{set: []uint64{20, 100}, expect: []byte{0x44, 0x80, 0x22, 0x4f, 0x01}},
+	}
+	for _, c := range cases {
+		bf := NewBitFieldFromSet(c.set)
+		buf := bytes.NewBuffer(nil)
+		assert.Nil(t, bf.MarshalCBOR(buf))
+		assert.Equal(t, c.expect, buf.Bytes(), "set %v", c.set)
+
+		var decoded BitField
+		assert.Nil(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		all, err := decoded.All(100)
+		assert.Nil(t, err)
+		assert.Equal(t, len(c.set), len(all))
+	}
+
+	_, err := NewBitFieldFromBytes([]byte{0x0c, 0x00})
+	assert.ErrorIs(t, err, ErrBitFieldDecode)
+	_, err = NewBitFieldFromBytes([]byte{0x0d})
+	assert.ErrorIs(t, err, ErrBitFieldDecode)
+}
+
+func TestBitFieldOps(t *testing.T) {
+	a := NewBitFieldFromSet([]uint64{1, 2, 3, 10, 11})
+	b := NewBitFieldFromSet([]uint64{3, 4, 11, 20})
+
+	a.Set(5)
+	assert.True(t, a.IsSet(5))
+	a.Unset(5)
+	assert.False(t, a.IsSet(5))
+
+	union, err := a.Union(b).All(100)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 10, 11, 20}, union)
+
+	intersect, err := a.Intersect(b).All(100)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{3, 11}, intersect)
+
+	subtract, err := a.Subtract(b).All(100)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2, 10}, subtract)
+	assert.Equal(t, uint64(5), a.Count())
+
+	var runs []BitRun
+	assert.Nil(t, a.ForEachRun(func(run BitRun) error {
+		runs = append(runs, run)
+		return nil
+	}))
+	assert.Equal(t, []BitRun{{false, 1}, {true, 3}, {false, 6}, {true, 2}}, runs)
+}
@@ -0,0 +1,152 @@
+package adt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// BufferedStore is a write-back Store. Blocks read from the underlying store are cached, and blocks
+// put into it are only hashed and kept in memory until Flush, which writes the blocks reachable
+// from the given roots and discards the intermediate ones.
+//
+// Use it for methods that modify the same HAMT/AMT several times before saving state:
+//
+//	store := adt.NewBufferedStore(adt.AdtStore(ctx))
+//	balances, err := adt.AsMap(store, state.Balances, adt.BalanceTableBitwidth)
+//	... several Put/Delete/Root calls ...
+//	state.Balances, err = balances.Root()
+//	err = store.Flush(state.Balances)
+type BufferedStore struct {
+	base Store
+	// blocks written since the last flush, not yet in the underlying store
+	write map[cid.Cid][]byte
+	// blocks known to be in the underlying store
+	read map[cid.Cid][]byte
+}
+
+var _ Store = &BufferedStore{}
+
+// NewBufferedStore wraps the `base` store with a write-back cache.
+func NewBufferedStore(base Store) *BufferedStore {
+	return &BufferedStore{
+		base:  base,
+		write: make(map[cid.Cid][]byte),
+		read:  make(map[cid.Cid][]byte),
+	}
+}
+
+// Context returns the context of underlying store.
+func (s *BufferedStore) Context() context.Context {
+	return s.base.Context()
+}
+
+// Get decodes the block `c` into `out`, reading it from the underlying store only on a cache miss.
+func (s *BufferedStore) Get(ctx context.Context, c cid.Cid, out interface{}) error {
+	unmarshalableObj, ok := out.(cbor.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("ipld store get method must be Unmarshalable")
+	}
+
+	data, ok := s.write[c]
+	if !ok {
+		data, ok = s.read[c]
+	}
+	if !ok {
+		var raw cbg.Deferred
+		if err := s.base.Get(ctx, c, &raw); err != nil {
+			return err
+		}
+		data = raw.Raw
+		s.read[c] = data
+	}
+	return unmarshalableObj.UnmarshalCBOR(bytes.NewReader(data))
+}
+
+// Put encodes `in` and computes its cid, the block is buffered until Flush.
+func (s *BufferedStore) Put(ctx context.Context, in interface{}) (cid.Cid, error) {
+	marshalableObj, ok := in.(cbor.Marshaler)
+	if !ok {
+		return cid.Undef, fmt.Errorf("ipld store put method must be marshalable")
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := marshalableObj.MarshalCBOR(buf); err != nil {
+		return cid.Undef, fmt.Errorf("marshal object fail")
+	}
+	data := buf.Bytes()
+
+	digest, err := sdk.HashBlake2b(ctx, data)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to hash block: %w", err)
+	}
+	hash, err := mh.Encode(digest[:], types.BLAKE2B256)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to encode multihash: %w", err)
+	}
+	c := cid.NewCidV1(types.DAGCBOR, hash)
+
+	if _, persisted := s.read[c]; !persisted {
+		s.write[c] = data
+	}
+	return c, nil
+}
+
+// Flush writes the buffered blocks reachable from `roots` to the underlying store, children before
+// parents. Buffered blocks that are not reachable from any root are dropped.
+func (s *BufferedStore) Flush(roots ...cid.Cid) error {
+	seen := make(map[cid.Cid]struct{})
+	for _, root := range roots {
+		if err := s.flush(root, seen); err != nil {
+			return err
+		}
+	}
+	s.write = make(map[cid.Cid][]byte)
+	return nil
+}
+
+// Pending returns the number of blocks waiting to be flushed.
+func (s *BufferedStore) Pending() int {
+	return len(s.write)
+}
+
+func (s *BufferedStore) flush(c cid.Cid, seen map[cid.Cid]struct{}) error {
+	if _, ok := seen[c]; ok {
+		return nil
+	}
+	seen[c] = struct{}{}
+
+	data, ok := s.write[c]
+	if !ok {
+		// already in the underlying store
+		return nil
+	}
+
+	var links []cid.Cid
+	if err := cbg.ScanForLinks(bytes.NewReader(data), func(link cid.Cid) {
+		links = append(links, link)
+	}); err != nil {
+		return fmt.Errorf("failed to scan links of block %v: %w", c, err)
+	}
+	for _, link := range links {
+		if err := s.flush(link, seen); err != nil {
+			return err
+		}
+	}
+
+	actual, err := s.base.Put(s.Context(), &cbg.Deferred{Raw: data})
+	if err != nil {
+		return fmt.Errorf("failed to write block %v: %w", c, err)
+	}
+	if !actual.Equals(c) {
+		return fmt.Errorf("block written as %v, expected %v", actual, c)
+	}
+	s.read[c] = data
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferedStore(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+
+	direct, err := MakeEmptyMap(AdtStore(ctx), BalanceTableBitwidth)
+	assert.Nil(t, err)
+
+	store := NewBufferedStore(AdtStore(ctx))
+	buffered, err := MakeEmptyMap(store, BalanceTableBitwidth)
+	assert.Nil(t, err)
+
+	for i := 0; i < 200; i++ {
+		val := big.NewInt(int64(i))
+		assert.Nil(t, direct.Put(types.ActorKey(i), &val))
+		assert.Nil(t, buffered.Put(types.ActorKey(i), &val))
+		// intermediate roots are only buffered
+		if i%50 == 0 {
+			_, err = buffered.Root()
+			assert.Nil(t, err)
+		}
+	}
+
+	expectRoot, err := direct.Root()
+	assert.Nil(t, err)
+	root, err := buffered.Root()
+	assert.Nil(t, err)
+	assert.Equal(t, expectRoot, root)
+	assert.NotZero(t, store.Pending())
+
+	assert.Nil(t, store.Flush(root))
+	assert.Zero(t, store.Pending())
+
+	loaded, err := AsMap(AdtStore(ctx), root, BalanceTableBitwidth)
+	assert.Nil(t, err)
+	var val big.Int
+	found, err := loaded.Get(types.ActorKey(123), &val)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(123), val.Int64())
+}
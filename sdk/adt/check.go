@@ -0,0 +1,419 @@
+package adt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	"github.com/minio/sha256-simd"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// hamtBucketSize max number of entries in a HAMT bucket before it's split into a child node,
+// same as go-hamt-ipld.
+const hamtBucketSize = 3
+
+// CheckReport is the result of checking an adt structure.
+type CheckReport struct {
+	// Nodes number of HAMT/AMT node blocks visited.
+	Nodes uint64
+	// Entries number of values found, for a Multimap it's the number of values in all the arrays.
+	Entries uint64
+	// Problems everything found invalid or not in canonical form, empty if the structure is consistent.
+	Problems []string
+}
+
+// OK returns true if no problem was found.
+func (r *CheckReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Err returns an error listing all the problems, or nil if there is none.
+func (r *CheckReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return fmt.Errorf("%d problems found: %s", len(r.Problems), strings.Join(r.Problems, "; "))
+}
+
+func (r *CheckReport) addProblem(format string, args ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// CheckMap walks the HAMT with root `root` and checks that it's in canonical form for the given bitwidth,
+// every key is stored at the position of its hash, and every value decodes into the object returned by
+// `newValue`. Value decoding is skipped if `newValue` is nil.
+func CheckMap(s Store, root cid.Cid, bitwidth int, newValue func() cbor.Unmarshaler) *CheckReport {
+	report := &CheckReport{}
+	checkHamt(s, root, bitwidth, report, func(key []byte, value *cbg.Deferred) {
+		report.Entries++
+		checkValue(report, fmt.Sprintf("key %x", key), value, newValue)
+	})
+	return report
+}
+
+// CheckSet walks the HAMT with root `root` and checks that it's a valid Set with the given bitwidth.
+func CheckSet(s Store, root cid.Cid, bitwidth int) *CheckReport {
+	return CheckMap(s, root, bitwidth, nil)
+}
+
+// CheckArray walks the AMT with root `root` and checks that it's in canonical form for the given
+// bitwidth, its count matches the number of values, and every value decodes into the object returned
+// by `newValue`. Value decoding is skipped if `newValue` is nil.
+func CheckArray(s Store, root cid.Cid, bitwidth int, newValue func() cbor.Unmarshaler) *CheckReport {
+	report := &CheckReport{}
+	checkAmt(s, root, bitwidth, report, func(i uint64, value *cbg.Deferred) {
+		report.Entries++
+		checkValue(report, fmt.Sprintf("index %d", i), value, newValue)
+	})
+	return report
+}
+
+// CheckMultimap checks the outer HAMT and every inner AMT of a Multimap, and that no key maps to an
+// empty array.
+func CheckMultimap(s Store, root cid.Cid, outerBitwidth, innerBitwidth int, newValue func() cbor.Unmarshaler) *CheckReport {
+	report := &CheckReport{}
+	checkHamt(s, root, outerBitwidth, report, func(key []byte, value *cbg.Deferred) {
+		var arrRoot cbg.CborCid
+		if err := arrRoot.UnmarshalCBOR(bytes.NewReader(value.Raw)); err != nil {
+			report.addProblem("value of key %x is not an array root: %v", key, err)
+			return
+		}
+		count := checkAmt(s, cid.Cid(arrRoot), innerBitwidth, report, func(i uint64, value *cbg.Deferred) {
+			report.Entries++
+			checkValue(report, fmt.Sprintf("key %x index %d", key, i), value, newValue)
+		})
+		if count == 0 {
+			report.addProblem("key %x maps to an empty array", key)
+		}
+	})
+	return report
+}
+
+// CheckBalanceTable checks the HAMT of a BalanceTable, and that every balance is positive as zero
+// balances are removed from the table.
+func CheckBalanceTable(s Store, root cid.Cid) *CheckReport {
+	report := &CheckReport{}
+	checkHamt(s, root, BalanceTableBitwidth, report, func(key []byte, value *cbg.Deferred) {
+		report.Entries++
+		var balance abi.TokenAmount
+		if err := balance.UnmarshalCBOR(bytes.NewReader(value.Raw)); err != nil {
+			report.addProblem("failed to decode balance of key %x: %v", key, err)
+			return
+		}
+		if balance.Sign() <= 0 {
+			report.addProblem("balance of key %x is not positive: %v", key, balance)
+		}
+	})
+	return report
+}
+
+func checkValue(report *CheckReport, at string, value *cbg.Deferred, newValue func() cbor.Unmarshaler) {
+	if newValue == nil {
+		return
+	}
+	if err := newValue().UnmarshalCBOR(bytes.NewReader(value.Raw)); err != nil {
+		report.addProblem("failed to decode value at %s: %v", at, err)
+	}
+}
+
+func checkHamt(s Store, root cid.Cid, bitwidth int, report *CheckReport, visit func(key []byte, value *cbg.Deferred)) {
+	if bitwidth < 1 || bitwidth > 8 {
+		report.addProblem("invalid hamt bitwidth %d", bitwidth)
+		return
+	}
+	checkHamtNode(s, root, bitwidth, nil, report, visit)
+}
+
+// checkHamtNode checks the node `c` reached through the pointer indexes in `path`.
+func checkHamtNode(s Store, c cid.Cid, bitwidth int, path []int, report *CheckReport, visit func(key []byte, value *cbg.Deferred)) {
+	var raw cbg.Deferred
+	if err := s.Get(s.Context(), c, &raw); err != nil {
+		report.addProblem("failed to load hamt node %v: %v", c, err)
+		return
+	}
+	report.Nodes++
+
+	var node hamt.Node
+	if err := node.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		report.addProblem("failed to decode hamt node %v: %v", c, err)
+		return
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := node.MarshalCBOR(buf); err != nil || !bytes.Equal(buf.Bytes(), raw.Raw) {
+		report.addProblem("hamt node %v is not canonically encoded", c)
+	}
+
+	width := 1 << bitwidth
+	if node.Bitfield.BitLen() > width {
+		report.addProblem("hamt node %v has bitfield wider than %d", c, width)
+		return
+	}
+	var indexes []int
+	for i := 0; i < width; i++ {
+		if node.Bitfield.Bit(i) == 1 {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) != len(node.Pointers) {
+		report.addProblem("hamt node %v has %d bits set but %d pointers", c, len(indexes), len(node.Pointers))
+		return
+	}
+	if len(path) > 0 && len(node.Pointers) == 0 {
+		report.addProblem("hamt node %v is empty", c)
+		return
+	}
+
+	shards, kvs := 0, 0
+	for i, pointer := range node.Pointers {
+		childPath := append(path[:len(path):len(path)], indexes[i])
+		switch {
+		case pointer.Link.Defined() && len(pointer.KVs) > 0:
+			report.addProblem("hamt node %v pointer %d has both link and bucket", c, i)
+		case pointer.Link.Defined():
+			shards++
+			checkHamtNode(s, pointer.Link, bitwidth, childPath, report, visit)
+		case len(pointer.KVs) == 0:
+			report.addProblem("hamt node %v pointer %d is an empty bucket", c, i)
+		default:
+			if len(pointer.KVs) > hamtBucketSize {
+				report.addProblem("hamt node %v pointer %d bucket has %d entries, max %d", c, i, len(pointer.KVs), hamtBucketSize)
+			}
+			kvs += len(pointer.KVs)
+			for j, kv := range pointer.KVs {
+				if j > 0 && bytes.Compare(pointer.KVs[j-1].Key, kv.Key) >= 0 {
+					report.addProblem("hamt node %v pointer %d bucket is not sorted", c, i)
+				}
+				if !hamtKeyOnPath(kv.Key, bitwidth, childPath) {
+					report.addProblem("key %x in hamt node %v is not at the position of its hash", kv.Key, c)
+				}
+				visit(kv.Key, kv.Value)
+			}
+		}
+	}
+	if len(path) > 0 && shards == 0 && kvs <= hamtBucketSize {
+		report.addProblem("hamt node %v has only %d entries and should be collapsed into its parent", c, kvs)
+	}
+}
+
+func hamtKeyOnPath(key []byte, bitwidth int, path []int) bool {
+	digest := sha256.Sum256(key)
+	hb := &hashBits{b: digest[:]}
+	for _, expect := range path {
+		idx, err := hb.next(bitwidth)
+		if err != nil || idx != expect {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAmt checks the AMT with root `root` and returns the number of values in it.
+func checkAmt(s Store, root cid.Cid, bitwidth int, report *CheckReport, visit func(i uint64, value *cbg.Deferred)) uint64 {
+	var raw cbg.Deferred
+	if err := s.Get(s.Context(), root, &raw); err != nil {
+		report.addProblem("failed to load amt root %v: %v", root, err)
+		return 0
+	}
+	report.Nodes++
+
+	var r amtRoot
+	if err := r.unmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		report.addProblem("failed to decode amt root %v: %v", root, err)
+		return 0
+	}
+	if r.bitWidth != uint64(bitwidth) || bitwidth < 1 || bitwidth > 8 {
+		report.addProblem("amt %v has bitwidth %d, expected %d", root, r.bitWidth, bitwidth)
+		return 0
+	}
+	if r.height > 64 {
+		report.addProblem("amt %v height %d is too large", root, r.height)
+		return 0
+	}
+	if r.height > 0 && !r.node.hasLinkAfterFirst(uint(bitwidth)) {
+		report.addProblem("amt %v height %d is not minimal", root, r.height)
+	}
+
+	count := checkAmtNode(s, root, &r.node, uint(bitwidth), r.height, 0, true, report, visit)
+	if count != r.count {
+		report.addProblem("amt %v count is %d, but has %d values", root, r.count, count)
+	}
+	return count
+}
+
+func checkAmtNode(s Store, c cid.Cid, nd *amtNode, bitwidth uint, height, offset uint64, isRoot bool, report *CheckReport, visit func(i uint64, value *cbg.Deferred)) uint64 {
+	if len(nd.bmap) != amtBmapBytes(bitwidth) {
+		report.addProblem("amt node %v bitmap has %d bytes, expected %d", c, len(nd.bmap), amtBmapBytes(bitwidth))
+		return 0
+	}
+	width := uint(1) << bitwidth
+	if bitwidth < 3 && nd.bmap[0]>>width != 0 {
+		report.addProblem("amt node %v bitmap has bits beyond width %d", c, width)
+	}
+	var positions []uint64
+	for x := uint(0); x < width; x++ {
+		if nd.bmap[x/8]&(1<<(x%8)) > 0 {
+			positions = append(positions, uint64(x))
+		}
+	}
+
+	switch {
+	case len(nd.links) > 0 && len(nd.values) > 0:
+		report.addProblem("amt node %v has both links and values", c)
+		return 0
+	case len(nd.links) == 0 && len(nd.values) == 0:
+		if !isRoot || len(positions) > 0 {
+			report.addProblem("amt node %v is empty", c)
+		}
+		return 0
+	case len(nd.values) > 0:
+		if height != 0 {
+			report.addProblem("amt node %v has values at height %d", c, height)
+			return 0
+		}
+		if len(positions) != len(nd.values) {
+			report.addProblem("amt node %v has %d bits set but %d values", c, len(positions), len(nd.values))
+			return 0
+		}
+		for i, x := range positions {
+			visit(offset+x, nd.values[i])
+		}
+		return uint64(len(nd.values))
+	default:
+		if height == 0 {
+			report.addProblem("amt node %v has links at height 0", c)
+			return 0
+		}
+		if len(positions) != len(nd.links) {
+			report.addProblem("amt node %v has %d bits set but %d links", c, len(positions), len(nd.links))
+			return 0
+		}
+		step := uint64(1)
+		for h := uint64(0); h < height; h++ {
+			step *= uint64(width)
+		}
+
+		var count uint64
+		for i, x := range positions {
+			link := nd.links[i]
+			var raw cbg.Deferred
+			if err := s.Get(s.Context(), link, &raw); err != nil {
+				report.addProblem("failed to load amt node %v: %v", link, err)
+				continue
+			}
+			report.Nodes++
+			var child amtNode
+			if err := child.unmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+				report.addProblem("failed to decode amt node %v: %v", link, err)
+				continue
+			}
+			count += checkAmtNode(s, link, &child, bitwidth, height-1, offset+x*step, false, report, visit)
+		}
+		return count
+	}
+}
+
+func amtBmapBytes(bitwidth uint) int {
+	if bitwidth <= 3 {
+		return 1
+	}
+	return 1 << (bitwidth - 3)
+}
+
+// amtRoot the serialized root of go-amt-ipld v4, [bitWidth, height, count, node].
+type amtRoot struct {
+	bitWidth uint64
+	height   uint64
+	count    uint64
+	node     amtNode
+}
+
+func (r *amtRoot) unmarshalCBOR(br io.Reader) error {
+	if err := readArrayHeader(br, 4); err != nil {
+		return err
+	}
+	for _, field := range []*uint64{&r.bitWidth, &r.height, &r.count} {
+		maj, extra, err := cbg.CborReadHeader(br)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		*field = extra
+	}
+	return r.node.unmarshalCBOR(br)
+}
+
+// amtNode the serialized node of go-amt-ipld v4, [bmap, links, values].
+type amtNode struct {
+	bmap   []byte
+	links  []cid.Cid
+	values []*cbg.Deferred
+}
+
+func (nd *amtNode) hasLinkAfterFirst(bitwidth uint) bool {
+	width := uint(1) << bitwidth
+	for x := uint(1); x < width; x++ {
+		if int(x/8) < len(nd.bmap) && nd.bmap[x/8]&(1<<(x%8)) > 0 {
+			return len(nd.links) > 0
+		}
+	}
+	return false
+}
+
+func (nd *amtNode) unmarshalCBOR(br io.Reader) error {
+	if err := readArrayHeader(br, 3); err != nil {
+		return err
+	}
+	var err error
+	if nd.bmap, err = cbg.ReadByteArray(br, 1<<(8-3)); err != nil {
+		return err
+	}
+
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray || extra > 1<<8 {
+		return fmt.Errorf("invalid amt links")
+	}
+	nd.links = make([]cid.Cid, extra)
+	for i := range nd.links {
+		if nd.links[i], err = cbg.ReadCid(br); err != nil {
+			return err
+		}
+	}
+
+	maj, extra, err = cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray || extra > 1<<8 {
+		return fmt.Errorf("invalid amt values")
+	}
+	nd.values = make([]*cbg.Deferred, extra)
+	for i := range nd.values {
+		nd.values[i] = &cbg.Deferred{}
+		if err := nd.values[i].UnmarshalCBOR(br); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readArrayHeader(br io.Reader, length uint64) error {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray || extra != length {
+		return fmt.Errorf("expected array of %d elements", length)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTokenAmount() cbor.Unmarshaler {
+	return &abi.TokenAmount{}
+}
+
+func TestCheck(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	m, err := MakeEmptyMap(store, BalanceTableBitwidth)
+	assert.Nil(t, err)
+	arr, err := MakeEmptyArray(store, 3)
+	assert.Nil(t, err)
+	mm, err := MakeEmptyMultimap(store, BalanceTableBitwidth, 3)
+	assert.Nil(t, err)
+	for i := 0; i < 300; i++ {
+		val := big.NewInt(int64(i + 1))
+		assert.Nil(t, m.Put(types.ActorKey(i), &val))
+		assert.Nil(t, arr.Set(uint64(i*7), &val))
+		assert.Nil(t, mm.Add(types.ActorKey(i%10), &val))
+	}
+	for i := 0; i < 300; i += 2 {
+		assert.Nil(t, m.Delete(types.ActorKey(i)))
+	}
+	mapRoot, err := m.Root()
+	assert.Nil(t, err)
+	arrRoot, err := arr.Root()
+	assert.Nil(t, err)
+	mmRoot, err := mm.Root()
+	assert.Nil(t, err)
+
+	report := CheckMap(store, mapRoot, BalanceTableBitwidth, newTokenAmount)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(150), report.Entries)
+
+	report = CheckArray(store, arrRoot, 3, newTokenAmount)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(300), report.Entries)
+
+	report = CheckMultimap(store, mmRoot, BalanceTableBitwidth, 3, newTokenAmount)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(300), report.Entries)
+
+	report = CheckMap(store, mapRoot, 5, nil)
+	assert.False(t, report.OK())
+	report = CheckArray(store, arrRoot, 5, nil)
+	assert.False(t, report.OK())
+	report = CheckMap(store, arrRoot, BalanceTableBitwidth, nil)
+	assert.False(t, report.OK())
+
+	emptyBalance, err := StoreEmptyMap(store, BalanceTableBitwidth)
+	assert.Nil(t, err)
+	bt, err := AsBalanceTable(store, emptyBalance)
+	assert.Nil(t, err)
+	addr, err := address.NewIDAddress(1)
+	assert.Nil(t, err)
+	assert.Nil(t, bt.Add(addr, big.NewInt(10)))
+	btRoot, err := bt.Root()
+	assert.Nil(t, err)
+	report = CheckBalanceTable(store, btRoot)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(1), report.Entries)
+}
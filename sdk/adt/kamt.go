@@ -0,0 +1,718 @@
+package adt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	fbig "github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+var (
+	// ErrKamtKeyOverflow returned when a big integer doesn't fit in a KamtKey.
+	ErrKamtKeyOverflow = errors.New("kamt key must be a non-negative integer of at most 256 bits")
+	// ErrKamtMaxDepth returned when a node is indexed by bits past the end of the key.
+	ErrKamtMaxDepth = errors.New("kamt index reads past the end of the key")
+)
+
+// kamtKeyBits the number of bits of a KamtKey.
+const kamtKeyBits = 256
+
+// KamtConfig configures the layout of a Kamt, it must be the same whenever the same Kamt is loaded.
+type KamtConfig struct {
+	// BitWidth the number of key bits consumed by every level, the branching factor is 2^BitWidth.
+	BitWidth int
+	// MaxArrayWidth the max number of entries stored in a bucket before it's split into a new node. Keys
+	// differing only in the last bits which don't make a whole index, eg. the last bit with a bit width of 5,
+	// can't be split and stay in the same bucket.
+	MaxArrayWidth int
+}
+
+// DefaultKamtConfig the configuration used by the builtin EVM actor for contract storage.
+var DefaultKamtConfig = KamtConfig{BitWidth: 5, MaxArrayWidth: 1}
+
+// KamtKey a 256-bit key of a Kamt, in big-endian order.
+type KamtKey [32]byte
+
+// KamtKeyFromBigInt converts a non-negative integer of at most 256 bits, eg. an EVM storage slot, into a key.
+func KamtKeyFromBigInt(v fbig.Int) (KamtKey, error) {
+	var k KamtKey
+	if v.Int == nil {
+		return k, nil
+	}
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		return k, ErrKamtKeyOverflow
+	}
+	v.FillBytes(k[:])
+	return k, nil
+}
+
+// BigInt returns the key as integer.
+func (k KamtKey) BigInt() fbig.Int {
+	return fbig.Int{Int: new(big.Int).SetBytes(k[:])}
+}
+
+// Kamt stores key-value pairs in a KAMT, a HAMT keyed by 256-bit integers without hashing.
+// Keys sharing a prefix are stored next to each other, and chains of nodes with a single child are
+// skipped by extensions, so sequential keys are cheap to read and write.
+// The encoding is compatible with fvm_ipld_kamt: a node is [bitfield, pointers], a pointer is either a
+// bucket of [key, value] pairs, a link, or [link, [bits, path]] for a link with an extension. Keys are
+// encoded as big-endian bytes without leading zeros, like U256 in the builtin actors.
+type Kamt struct {
+	lastCid cid.Cid
+	root    *kamtNode
+	store   Store
+	conf    KamtConfig
+}
+
+// AsKamt interprets a store as a KAMT with root `r`.
+func AsKamt(s Store, r cid.Cid, conf KamtConfig) (*Kamt, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	root := newKamtNode()
+	if err := s.Get(s.Context(), r, root); err != nil {
+		return nil, fmt.Errorf("failed to load kamt node: %w", err)
+	}
+	return &Kamt{
+		lastCid: r,
+		root:    root,
+		store:   s,
+		conf:    conf,
+	}, nil
+}
+
+// MakeEmptyKamt creates a new KAMT with no entries.
+func MakeEmptyKamt(s Store, conf KamtConfig) (*Kamt, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	return &Kamt{
+		root:  newKamtNode(),
+		store: s,
+		conf:  conf,
+	}, nil
+}
+
+// StoreEmptyKamt creates and stores a new empty KAMT, returning its CID.
+func StoreEmptyKamt(s Store, conf KamtConfig) (cid.Cid, error) {
+	k, err := MakeEmptyKamt(s, conf)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return k.Root()
+}
+
+// Root returns the root cid of the KAMT, flushing modified nodes to the store.
+func (k *Kamt) Root() (cid.Cid, error) {
+	if err := k.root.flush(k.store); err != nil {
+		return cid.Undef, err
+	}
+	c, err := k.store.Put(k.store.Context(), k.root)
+	if err != nil {
+		return cid.Undef, err
+	}
+	k.lastCid = c
+	return c, nil
+}
+
+// Get retrieves the value at `key` into `out`, if the key is present and `out` is non-nil.
+// Returns whether the key was found.
+func (k *Kamt) Get(key KamtKey, out cbor.Unmarshaler) (bool, error) {
+	nd := k.root
+	at := 0
+	for {
+		idx, err := kamtIndex(key[:], kamtKeyBits, at, k.conf.BitWidth)
+		if err != nil {
+			return false, err
+		}
+		at += k.conf.BitWidth
+		if nd.bitfield.Bit(idx) == 0 {
+			return false, nil
+		}
+		p := nd.pointers[nd.indexForBitPos(idx)]
+		if p.isLink() {
+			if p.ext.matchLen(&key, at) < p.ext.bits {
+				return false, nil
+			}
+			at += p.ext.bits
+			if nd, err = p.load(k.store); err != nil {
+				return false, err
+			}
+			continue
+		}
+		for _, kv := range p.kvs {
+			if kv.key == key {
+				if out != nil {
+					if err := out.UnmarshalCBOR(bytes.NewReader(kv.value.Raw)); err != nil {
+						return false, fmt.Errorf("failed to decode kamt value %x: %w", key, err)
+					}
+				}
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// Has checks whether `key` is present.
+func (k *Kamt) Has(key KamtKey) (bool, error) {
+	return k.Get(key, nil)
+}
+
+// Put adds value `v` with key `key`, overwriting the previous value.
+func (k *Kamt) Put(key KamtKey, v cbor.Marshaler) error {
+	buf := bytes.NewBuffer(nil)
+	if err := v.MarshalCBOR(buf); err != nil {
+		return err
+	}
+	kv := &kamtKV{key: key, value: &cbg.Deferred{Raw: buf.Bytes()}}
+	return k.root.put(k.store, k.conf, 0, kv)
+}
+
+// TryDelete removes the value at `key`, if it exists.
+// Returns whether the key was previously present.
+func (k *Kamt) TryDelete(key KamtKey) (bool, error) {
+	return k.root.delete(k.store, k.conf, 0, key)
+}
+
+// Delete removes the value at `key`, expecting it to exist.
+func (k *Kamt) Delete(key KamtKey) error {
+	found, err := k.TryDelete(key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no such key %x to delete", key)
+	}
+	return nil
+}
+
+// ForEach iterates all entries in ascending key order, deserializing each value in turn into `out` and
+// then calling a function with the corresponding key.
+// Iteration halts if the function returns an error.
+// If the output parameter is nil, deserialization is skipped.
+func (k *Kamt) ForEach(out cbor.Unmarshaler, fn func(key KamtKey) error) error {
+	return k.root.forEach(k.store, func(kv *kamtKV) error {
+		if out != nil {
+			if err := out.UnmarshalCBOR(bytes.NewReader(kv.value.Raw)); err != nil {
+				return fmt.Errorf("failed to decode kamt value %x: %w", kv.key, err)
+			}
+		}
+		return fn(kv.key)
+	})
+}
+
+// IsEmpty checks whether the KAMT has no entries.
+func (k *Kamt) IsEmpty() bool {
+	return len(k.root.pointers) == 0
+}
+
+func (conf KamtConfig) validate() error {
+	if conf.BitWidth < 1 || conf.BitWidth > 8 {
+		return fmt.Errorf("invalid kamt bit width %d", conf.BitWidth)
+	}
+	if conf.MaxArrayWidth < 1 {
+		return fmt.Errorf("invalid kamt max array width %d", conf.MaxArrayWidth)
+	}
+	return nil
+}
+
+type kamtKV struct {
+	key   KamtKey
+	value *cbg.Deferred
+}
+
+// kamtExt a path of key bits skipped between a node and its child.
+type kamtExt struct {
+	bits int
+	path []byte
+}
+
+// appendBits appends `n` bits of `src` starting at bit `from`.
+func (e kamtExt) appendBits(src []byte, from, n int) kamtExt {
+	path := append([]byte(nil), e.path...)
+	for i := 0; i < n; i++ {
+		if e.bits%8 == 0 {
+			path = append(path, 0)
+		}
+		path[e.bits/8] |= keyBit(src, from+i) << (7 - uint(e.bits%8))
+		e.bits++
+	}
+	e.path = path
+	return e
+}
+
+// slice returns the bits of the extension in [from, to).
+func (e kamtExt) slice(from, to int) kamtExt {
+	return kamtExt{}.appendBits(e.path, from, to-from)
+}
+
+// matchLen returns the number of leading bits of the extension matching `key` at bit `at`.
+func (e kamtExt) matchLen(key *KamtKey, at int) int {
+	for i := 0; i < e.bits; i++ {
+		if at+i >= len(key)*8 || keyBit(e.path, i) != keyBit(key[:], at+i) {
+			return i
+		}
+	}
+	return e.bits
+}
+
+// kamtIndex reads `n` bits of the first `size` bits of `b` starting at bit `at`. Reads past `size` are rejected,
+// nodes are only created where a whole index fits in the key, see kamtNode.put.
+func kamtIndex(b []byte, size, at, n int) (int, error) {
+	if at < 0 || at+n > size {
+		return 0, fmt.Errorf("%w: bits [%d, %d) of %d", ErrKamtMaxDepth, at, at+n, size)
+	}
+	idx := 0
+	for i := at; i < at+n; i++ {
+		idx = idx<<1 | int(keyBit(b, i))
+	}
+	return idx, nil
+}
+
+func keyBit(b []byte, i int) byte {
+	return (b[i/8] >> (7 - uint(i%8))) & 1
+}
+
+type kamtPointer struct {
+	// kvs the entries of a bucket, sorted by key, nil for a link
+	kvs []*kamtKV
+
+	link  cid.Cid
+	ext   kamtExt
+	cache *kamtNode
+	// dirty the cached child was modified since it was last stored
+	dirty bool
+}
+
+func (p *kamtPointer) isLink() bool {
+	return p.kvs == nil
+}
+
+func (p *kamtPointer) load(s Store) (*kamtNode, error) {
+	if p.cache == nil {
+		nd := newKamtNode()
+		if err := s.Get(s.Context(), p.link, nd); err != nil {
+			return nil, fmt.Errorf("failed to load kamt node %s: %w", p.link, err)
+		}
+		p.cache = nd
+	}
+	return p.cache, nil
+}
+
+type kamtNode struct {
+	bitfield *big.Int
+	pointers []*kamtPointer
+}
+
+func newKamtNode() *kamtNode {
+	return &kamtNode{bitfield: new(big.Int)}
+}
+
+func (nd *kamtNode) indexForBitPos(bp int) int {
+	idx := 0
+	for i := 0; i < bp; i++ {
+		idx += int(nd.bitfield.Bit(i))
+	}
+	return idx
+}
+
+func (nd *kamtNode) insertPointer(idx int, p *kamtPointer) {
+	i := nd.indexForBitPos(idx)
+	nd.bitfield.SetBit(nd.bitfield, idx, 1)
+	nd.pointers = append(nd.pointers, nil)
+	copy(nd.pointers[i+1:], nd.pointers[i:])
+	nd.pointers[i] = p
+}
+
+func (nd *kamtNode) removePointer(idx int) {
+	i := nd.indexForBitPos(idx)
+	nd.bitfield.SetBit(nd.bitfield, idx, 0)
+	nd.pointers = append(nd.pointers[:i], nd.pointers[i+1:]...)
+}
+
+func (nd *kamtNode) put(s Store, conf KamtConfig, at int, kv *kamtKV) error {
+	idx, err := kamtIndex(kv.key[:], kamtKeyBits, at, conf.BitWidth)
+	if err != nil {
+		return err
+	}
+	at += conf.BitWidth
+	if nd.bitfield.Bit(idx) == 0 {
+		nd.insertPointer(idx, &kamtPointer{kvs: []*kamtKV{kv}})
+		return nil
+	}
+	p := nd.pointers[nd.indexForBitPos(idx)]
+
+	if p.isLink() {
+		n := p.ext.matchLen(&kv.key, at)
+		if n < p.ext.bits {
+			// the key leaves the extension, split it with a new node where they diverge
+			n -= n % conf.BitWidth
+			midIdx, err := kamtIndex(p.ext.path, p.ext.bits, n, conf.BitWidth)
+			if err != nil {
+				return err
+			}
+			mid := newKamtNode()
+			mid.insertPointer(midIdx, &kamtPointer{
+				link:  p.link,
+				ext:   p.ext.slice(n+conf.BitWidth, p.ext.bits),
+				cache: p.cache,
+				dirty: p.dirty,
+			})
+			if err := mid.put(s, conf, at+n, kv); err != nil {
+				return err
+			}
+			*p = kamtPointer{ext: p.ext.slice(0, n), cache: mid, dirty: true}
+			return nil
+		}
+		child, err := p.load(s)
+		if err != nil {
+			return err
+		}
+		if err := child.put(s, conf, at+p.ext.bits, kv); err != nil {
+			return err
+		}
+		p.dirty = true
+		return nil
+	}
+
+	i := sort.Search(len(p.kvs), func(i int) bool {
+		return bytes.Compare(p.kvs[i].key[:], kv.key[:]) >= 0
+	})
+	if i < len(p.kvs) && p.kvs[i].key == kv.key {
+		p.kvs[i] = kv
+		return nil
+	}
+	if len(p.kvs) < conf.MaxArrayWidth {
+		p.kvs = append(p.kvs, nil)
+		copy(p.kvs[i+1:], p.kvs[i:])
+		p.kvs[i] = kv
+		return nil
+	}
+
+	// the bucket is full, move its entries into a new node below the prefix they all share
+	kvs := append(p.kvs, kv)
+	start := at
+	shared := len(kv.key)*8 - start
+	for _, other := range p.kvs {
+		ext := kamtExt{}.appendBits(other.key[:], start, shared)
+		shared = ext.matchLen(&kv.key, start)
+	}
+	shared -= shared % conf.BitWidth
+	if start+shared+conf.BitWidth > kamtKeyBits {
+		// not enough key bits are left below the shared prefix for a whole index, eg. with a bit width of 5
+		// keys differing only in their last bit, the bucket holds more than MaxArrayWidth entries
+		p.kvs = append(p.kvs, nil)
+		copy(p.kvs[i+1:], p.kvs[i:])
+		p.kvs[i] = kv
+		return nil
+	}
+	child := newKamtNode()
+	for _, e := range kvs {
+		if err := child.put(s, conf, start+shared, e); err != nil {
+			return err
+		}
+	}
+	*p = kamtPointer{ext: kamtExt{}.appendBits(kv.key[:], start, shared), cache: child, dirty: true}
+	return nil
+}
+
+func (nd *kamtNode) delete(s Store, conf KamtConfig, at int, key KamtKey) (bool, error) {
+	idx, err := kamtIndex(key[:], kamtKeyBits, at, conf.BitWidth)
+	if err != nil {
+		return false, err
+	}
+	at += conf.BitWidth
+	if nd.bitfield.Bit(idx) == 0 {
+		return false, nil
+	}
+	p := nd.pointers[nd.indexForBitPos(idx)]
+
+	if p.isLink() {
+		if p.ext.matchLen(&key, at) < p.ext.bits {
+			return false, nil
+		}
+		child, err := p.load(s)
+		if err != nil {
+			return false, err
+		}
+		found, err := child.delete(s, conf, at+p.ext.bits, key)
+		if err != nil || !found {
+			return found, err
+		}
+		p.dirty = true
+		return true, p.clean(conf)
+	}
+
+	for i, kv := range p.kvs {
+		if kv.key == key {
+			if len(p.kvs) == 1 {
+				nd.removePointer(idx)
+			} else {
+				p.kvs = append(p.kvs[:i], p.kvs[i+1:]...)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clean keeps the child of a link canonical after a deletion: a child with a single link is replaced by
+// its own child with the extensions joined, and a child with few enough entries is replaced by a bucket.
+func (p *kamtPointer) clean(conf KamtConfig) error {
+	child := p.cache
+	switch len(child.pointers) {
+	case 0:
+		return fmt.Errorf("kamt node has no pointers")
+	case 1:
+		only := child.pointers[0]
+		if !only.isLink() {
+			*p = kamtPointer{kvs: only.kvs}
+			return nil
+		}
+		idx := child.bitfield.TrailingZeroBits()
+		ext := p.ext.appendBits([]byte{byte(idx << (8 - conf.BitWidth))}, 0, conf.BitWidth)
+		ext = ext.appendBits(only.ext.path, 0, only.ext.bits)
+		*p = kamtPointer{link: only.link, ext: ext, cache: only.cache, dirty: only.dirty}
+		return nil
+	}
+
+	var kvs []*kamtKV
+	for _, cp := range child.pointers {
+		if cp.isLink() {
+			return nil
+		}
+		kvs = append(kvs, cp.kvs...)
+		if len(kvs) > conf.MaxArrayWidth {
+			return nil
+		}
+	}
+	// pointers are ordered by index, so the entries are already sorted
+	*p = kamtPointer{kvs: kvs}
+	return nil
+}
+
+func (nd *kamtNode) flush(s Store) error {
+	for _, p := range nd.pointers {
+		if !p.isLink() || !p.dirty {
+			continue
+		}
+		if err := p.cache.flush(s); err != nil {
+			return err
+		}
+		c, err := s.Put(s.Context(), p.cache)
+		if err != nil {
+			return fmt.Errorf("failed to store kamt node: %w", err)
+		}
+		p.link = c
+		p.dirty = false
+	}
+	return nil
+}
+
+func (nd *kamtNode) forEach(s Store, fn func(kv *kamtKV) error) error {
+	for _, p := range nd.pointers {
+		if !p.isLink() {
+			for _, kv := range p.kvs {
+				if err := fn(kv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		child, err := p.load(s)
+		if err != nil {
+			return err
+		}
+		if err := child.forEach(s, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nd *kamtNode) MarshalCBOR(w io.Writer) error {
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, 2); err != nil {
+		return err
+	}
+	if err := writeKamtBytes(w, nd.bitfield.Bytes()); err != nil {
+		return err
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, uint64(len(nd.pointers))); err != nil {
+		return err
+	}
+	for _, p := range nd.pointers {
+		if err := p.marshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *kamtPointer) marshalCBOR(w io.Writer) error {
+	if p.isLink() {
+		if p.dirty {
+			return fmt.Errorf("kamt node must be flushed before it's stored")
+		}
+		if p.ext.bits == 0 {
+			return cbg.WriteCid(w, p.link)
+		}
+		if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, 2); err != nil {
+			return err
+		}
+		if err := cbg.WriteCid(w, p.link); err != nil {
+			return err
+		}
+		if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, 2); err != nil {
+			return err
+		}
+		if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(p.ext.bits)); err != nil {
+			return err
+		}
+		return writeKamtBytes(w, p.ext.path)
+	}
+
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, uint64(len(p.kvs))); err != nil {
+		return err
+	}
+	for _, kv := range p.kvs {
+		if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, 2); err != nil {
+			return err
+		}
+		if err := writeKamtBytes(w, bytes.TrimLeft(kv.key[:], "\x00")); err != nil {
+			return err
+		}
+		if _, err := w.Write(kv.value.Raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeKamtBytes(w io.Writer, b []byte) error {
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajByteString, uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func (nd *kamtNode) UnmarshalCBOR(br io.Reader) error {
+	if err := readArrayHeader(br, 2); err != nil {
+		return err
+	}
+	bitfield, err := cbg.ReadByteArray(br, 32)
+	if err != nil {
+		return err
+	}
+	nd.bitfield = new(big.Int).SetBytes(bitfield)
+
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray || extra > 256 {
+		return fmt.Errorf("invalid kamt pointers")
+	}
+	nd.pointers = make([]*kamtPointer, extra)
+	for i := range nd.pointers {
+		var raw cbg.Deferred
+		if err := raw.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+		if nd.pointers[i], err = unmarshalKamtPointer(raw.Raw); err != nil {
+			return err
+		}
+	}
+
+	popcount := 0
+	for _, word := range nd.bitfield.Bits() {
+		for ; word != 0; word &= word - 1 {
+			popcount++
+		}
+	}
+	if popcount != len(nd.pointers) {
+		return fmt.Errorf("kamt bitfield has %d bits set for %d pointers", popcount, len(nd.pointers))
+	}
+	return nil
+}
+
+func unmarshalKamtPointer(raw []byte) (*kamtPointer, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty kamt pointer")
+	}
+	br := bytes.NewReader(raw)
+	if raw[0]>>5 == cbg.MajTag {
+		link, err := cbg.ReadCid(br)
+		if err != nil {
+			return nil, err
+		}
+		return &kamtPointer{link: link}, nil
+	}
+
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if maj != cbg.MajArray || extra == 0 || extra > 256 {
+		return nil, fmt.Errorf("invalid kamt pointer")
+	}
+	if next, err := br.ReadByte(); err != nil {
+		return nil, err
+	} else if err := br.UnreadByte(); err != nil {
+		return nil, err
+	} else if next>>5 == cbg.MajTag {
+		// a link with an extension
+		if extra != 2 {
+			return nil, fmt.Errorf("invalid kamt link with extension")
+		}
+		p := &kamtPointer{}
+		if p.link, err = cbg.ReadCid(br); err != nil {
+			return nil, err
+		}
+		if err := readArrayHeader(br, 2); err != nil {
+			return nil, err
+		}
+		maj, bits, err := cbg.CborReadHeader(br)
+		if err != nil {
+			return nil, err
+		}
+		if maj != cbg.MajUnsignedInt || bits == 0 || bits > 256 {
+			return nil, fmt.Errorf("invalid kamt extension length")
+		}
+		path, err := cbg.ReadByteArray(br, 32)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(path)) != (bits+7)/8 {
+			return nil, fmt.Errorf("kamt extension of %d bits has %d bytes", bits, len(path))
+		}
+		p.ext = kamtExt{bits: int(bits), path: path}
+		return p, nil
+	}
+
+	p := &kamtPointer{kvs: make([]*kamtKV, extra)}
+	for i := range p.kvs {
+		if err := readArrayHeader(br, 2); err != nil {
+			return nil, err
+		}
+		key, err := cbg.ReadByteArray(br, 32)
+		if err != nil {
+			return nil, err
+		}
+		kv := &kamtKV{value: &cbg.Deferred{}}
+		copy(kv.key[32-len(key):], key)
+		if err := kv.value.UnmarshalCBOR(br); err != nil {
+			return nil, err
+		}
+		p.kvs[i] = kv
+	}
+	return p, nil
+}
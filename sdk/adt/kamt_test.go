@@ -0,0 +1,313 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func kamtKey(i uint64) KamtKey {
+	k, _ := KamtKeyFromBigInt(big.NewIntUnsigned(i))
+	return k
+}
+
+func TestKamt(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	k, err := MakeEmptyKamt(store, DefaultKamtConfig)
+	assert.Nil(t, err)
+	for i := uint64(0); i < 200; i++ {
+		val := cbg.CborInt(i * 2)
+		assert.Nil(t, k.Put(kamtKey(i), &val))
+	}
+	// sparse keys sharing a long prefix with the sequential ones
+	far := kamtKey(1 << 40)
+	val := cbg.CborInt(1)
+	assert.Nil(t, k.Put(far, &val))
+
+	root, err := k.Root()
+	assert.Nil(t, err)
+	k, err = AsKamt(store, root, DefaultKamtConfig)
+	assert.Nil(t, err)
+
+	var out cbg.CborInt
+	found, err := k.Get(kamtKey(150), &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, cbg.CborInt(300), out)
+	found, err = k.Has(kamtKey(1000))
+	assert.Nil(t, err)
+	assert.False(t, found)
+	found, err = k.Has(far)
+	assert.Nil(t, err)
+	assert.True(t, found)
+
+	var keys []KamtKey
+	assert.Nil(t, k.ForEach(nil, func(key KamtKey) error {
+		keys = append(keys, key)
+		return nil
+	}))
+	assert.Equal(t, 201, len(keys))
+	for i := 1; i < len(keys); i++ {
+		assert.Equal(t, -1, bytes.Compare(keys[i-1][:], keys[i][:]))
+	}
+
+	for i := uint64(0); i < 200; i++ {
+		assert.Nil(t, k.Delete(kamtKey(i)))
+	}
+	assert.NotNil(t, k.Delete(kamtKey(0)))
+	found, err = k.Get(far, &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Nil(t, k.Delete(far))
+	assert.True(t, k.IsEmpty())
+}
+
+func TestKamtCanonical(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	for _, conf := range []KamtConfig{DefaultKamtConfig, {BitWidth: 8, MaxArrayWidth: 3}, {BitWidth: 3, MaxArrayWidth: 2}} {
+		r := rand.New(rand.NewSource(1))
+		keys := make([]KamtKey, 300)
+		for i := range keys {
+			// mix sequential, clustered and random keys
+			switch i % 3 {
+			case 0:
+				keys[i] = kamtKey(uint64(i))
+			case 1:
+				keys[i] = kamtKey(1<<32 + uint64(i)*13)
+			default:
+				r.Read(keys[i][:])
+			}
+		}
+
+		full, err := MakeEmptyKamt(store, conf)
+		assert.Nil(t, err)
+		for _, key := range keys {
+			val := cbg.CborInt(key[31])
+			assert.Nil(t, full.Put(key, &val))
+		}
+		for i := 0; i < len(keys); i += 2 {
+			_, err := full.TryDelete(keys[i])
+			assert.Nil(t, err)
+		}
+		fullRoot, err := full.Root()
+		assert.Nil(t, err)
+
+		half, err := MakeEmptyKamt(store, conf)
+		assert.Nil(t, err)
+		for i := len(keys) - 1; i >= 0; i-- {
+			if i%2 == 1 {
+				val := cbg.CborInt(keys[i][31])
+				assert.Nil(t, half.Put(keys[i], &val))
+			}
+		}
+		halfRoot, err := half.Root()
+		assert.Nil(t, err)
+		assert.Equal(t, halfRoot, fullRoot)
+	}
+}
+
+func TestKamtEncoding(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	k, err := MakeEmptyKamt(store, DefaultKamtConfig)
+	assert.Nil(t, err)
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, k.root.MarshalCBOR(buf))
+	assert.Equal(t, []byte{0x82, 0x40, 0x80}, buf.Bytes())
+
+	val := cbg.CborInt(7)
+	assert.Nil(t, k.Put(kamtKey(1), &val))
+	buf.Reset()
+	assert.Nil(t, k.root.MarshalCBOR(buf))
+	// [bitfield 0b1, [[[h'01', 7]]]]
+	assert.Equal(t, []byte{0x82, 0x41, 0x01, 0x81, 0x81, 0x82, 0x41, 0x01, 0x07}, buf.Bytes())
+
+	// a second key in the same bucket moves both below an extension
+	assert.Nil(t, k.Put(kamtKey(2), &val))
+	root, err := k.Root()
+	assert.Nil(t, err)
+	p := k.root.pointers[0]
+	assert.True(t, p.isLink())
+	assert.Equal(t, 245, p.ext.bits)
+
+	// the child at bit 250 holds both keys: [bitfield 0b11, [[[h'01', 7]], [[h'02', 7]]]]
+	child := []byte{0x82, 0x41, 0x03, 0x82, 0x81, 0x82, 0x41, 0x01, 0x07, 0x81, 0x82, 0x41, 0x02, 0x07}
+	childCid, err := cid.Prefix{Version: 1, Codec: cid.DagCBOR, MhType: multihash.BLAKE2B_MIN + 31, MhLength: 32}.Sum(child)
+	assert.Nil(t, err)
+	assert.Equal(t, childCid, p.link)
+	var raw cbg.Deferred
+	assert.Nil(t, store.Get(ctx, childCid, &raw))
+	assert.Equal(t, child, raw.Raw)
+
+	// [bitfield 0b1, [[link, [245, h'00' * 31]]]]
+	expect := []byte{0x82, 0x41, 0x01, 0x81, 0x82, 0xd8, 0x2a, 0x58, 0x27, 0x00}
+	expect = append(expect, childCid.Bytes()...)
+	expect = append(expect, 0x82, 0x18, 0xf5, 0x58, 0x1f)
+	expect = append(expect, make([]byte, 31)...)
+	assert.Nil(t, store.Get(ctx, root, &raw))
+	assert.Equal(t, expect, raw.Raw)
+
+	// with a bit width of 8, keys 0x0100 and 0x0200 share 240 bits: the root index takes 8, the extension 232
+	// and the child is indexed by bits 240-247
+	k, err = MakeEmptyKamt(store, KamtConfig{BitWidth: 8, MaxArrayWidth: 1})
+	assert.Nil(t, err)
+	assert.Nil(t, k.Put(kamtKey(0x0100), &val))
+	assert.Nil(t, k.Put(kamtKey(0x0200), &val))
+	_, err = k.Root()
+	assert.Nil(t, err)
+	p = k.root.pointers[0]
+	assert.True(t, p.isLink())
+	assert.Equal(t, 232, p.ext.bits)
+	buf.Reset()
+	assert.Nil(t, p.cache.MarshalCBOR(buf))
+	// [bitfield 0b110, [[[h'0100', 7]], [[h'0200', 7]]]]
+	assert.Equal(t, []byte{0x82, 0x41, 0x06, 0x82, 0x81, 0x82, 0x42, 0x01, 0x00, 0x07, 0x81, 0x82, 0x42, 0x02, 0x00, 0x07}, buf.Bytes())
+}
+
+// kamtVector a case written by tools/kamt_vectors with the Rust fvm_ipld_kamt.
+type kamtVector struct {
+	Name          string `json:"name"`
+	BitWidth      int    `json:"bit_width"`
+	MaxArrayWidth int    `json:"max_array_width"`
+	Ops           []struct {
+		Key   string  `json:"key"`
+		Value *uint64 `json:"value"`
+	} `json:"ops"`
+	Root   string            `json:"root"`
+	Blocks map[string]string `json:"blocks"`
+}
+
+// TestKamtVectors checks the roots and blocks built by Kamt against those of the Rust fvm_ipld_kamt, generated by
+// `cargo run -p kamt_vectors > sdk/adt/testdata/kamt_vectors.json`.
+func TestKamtVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/kamt_vectors.json")
+	if os.IsNotExist(err) {
+		t.Skip("testdata/kamt_vectors.json not generated, run cargo run -p kamt_vectors")
+	}
+	assert.Nil(t, err)
+	var vectors []kamtVector
+	assert.Nil(t, json.Unmarshal(data, &vectors))
+
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			k, err := MakeEmptyKamt(store, KamtConfig{BitWidth: v.BitWidth, MaxArrayWidth: v.MaxArrayWidth})
+			assert.Nil(t, err)
+			for _, op := range v.Ops {
+				var key KamtKey
+				b, err := hex.DecodeString(op.Key)
+				assert.Nil(t, err)
+				copy(key[:], b)
+				if op.Value == nil {
+					_, err = k.TryDelete(key)
+				} else {
+					val := cbg.CborInt(*op.Value)
+					err = k.Put(key, &val)
+				}
+				assert.Nil(t, err)
+			}
+			root, err := k.Root()
+			assert.Nil(t, err)
+			assert.Equal(t, v.Root, root.String())
+
+			for c, block := range v.Blocks {
+				blockCid, err := cid.Decode(c)
+				assert.Nil(t, err)
+				var raw cbg.Deferred
+				assert.Nil(t, store.Get(ctx, blockCid, &raw))
+				assert.Equal(t, block, hex.EncodeToString(raw.Raw))
+			}
+		})
+	}
+}
+
+func TestKamtLastBit(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	// with a bit width of 5 the last key bit can't index a node, keys 0 and 1 share a bucket
+	k, err := MakeEmptyKamt(store, DefaultKamtConfig)
+	assert.Nil(t, err)
+	val := cbg.CborInt(7)
+	assert.Nil(t, k.Put(kamtKey(0), &val))
+	assert.Nil(t, k.Put(kamtKey(1), &val))
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, k.root.MarshalCBOR(buf))
+	// [bitfield 0b1, [[[h'', 7], [h'01', 7]]]]
+	assert.Equal(t, []byte{0x82, 0x41, 0x01, 0x81, 0x82, 0x82, 0x40, 0x07, 0x82, 0x41, 0x01, 0x07}, buf.Bytes())
+
+	// a third key splits the bucket down to bit 250, where 0 and 1 still share one
+	assert.Nil(t, k.Put(kamtKey(2), &val))
+	_, err = k.Root()
+	assert.Nil(t, err)
+	p := k.root.pointers[0]
+	assert.Equal(t, 245, p.ext.bits)
+	assert.Equal(t, 2, len(p.cache.pointers))
+	assert.Equal(t, 2, len(p.cache.pointers[0].kvs))
+
+	assert.Nil(t, k.Delete(kamtKey(2)))
+	buf.Reset()
+	assert.Nil(t, k.root.MarshalCBOR(buf))
+	assert.Equal(t, []byte{0x82, 0x41, 0x01, 0x81, 0x82, 0x82, 0x40, 0x07, 0x82, 0x41, 0x01, 0x07}, buf.Bytes())
+}
+
+func TestKamtIndex(t *testing.T) {
+	key := kamtKey(0b10110)
+	idx, err := kamtIndex(key[:], kamtKeyBits, 250, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 0b01011, idx)
+	idx, err = kamtIndex(key[:], kamtKeyBits, 251, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 0b10110, idx)
+
+	_, err = kamtIndex(key[:], kamtKeyBits, 252, 5)
+	assert.ErrorIs(t, err, ErrKamtMaxDepth)
+	_, err = kamtIndex(key[:], kamtKeyBits, 256, 1)
+	assert.ErrorIs(t, err, ErrKamtMaxDepth)
+
+	// an extension pointing past the end of the key is rejected instead of read as zeros
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+	leaf, err := store.Put(ctx, newKamtNode())
+	assert.Nil(t, err)
+	nd := newKamtNode()
+	nd.insertPointer(0, &kamtPointer{link: leaf, ext: kamtExt{bits: 251, path: make([]byte, 32)}})
+	root, err := store.Put(ctx, nd)
+	assert.Nil(t, err)
+	k, err := AsKamt(store, root, DefaultKamtConfig)
+	assert.Nil(t, err)
+	_, err = k.Has(kamtKey(0))
+	assert.ErrorIs(t, err, ErrKamtMaxDepth)
+}
+
+func TestKamtKeyFromBigInt(t *testing.T) {
+	k, err := KamtKeyFromBigInt(big.NewInt(0x0102))
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x01), k[30])
+	assert.Equal(t, byte(0x02), k[31])
+	assert.Equal(t, big.NewInt(0x0102), k.BigInt())
+
+	_, err = KamtKeyFromBigInt(big.NewInt(-1))
+	assert.ErrorIs(t, err, ErrKamtKeyOverflow)
+	_, err = KamtKeyFromBigInt(big.Lsh(big.NewInt(1), 256))
+	assert.ErrorIs(t, err, ErrKamtKeyOverflow)
+}
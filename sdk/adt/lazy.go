@@ -0,0 +1,207 @@
+package adt
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// LazyField a state field which is loaded on first use, eg. LazyMap.
+// The generated entry code saves the state after a method returns if any of its lazy fields is dirty.
+type LazyField interface {
+	IsDirty() bool
+}
+
+// lazy the handle shared by lazy fields, it's encoded as the root cid of the collection so a lazy field can
+// replace a cid.Cid field without changing the state layout.
+type lazy[T interface{ Root() (cid.Cid, error) }] struct {
+	root   cid.Cid
+	value  T
+	loaded bool
+	dirty  bool
+}
+
+// Cid returns the root cid of the collection as of the last flush.
+func (l *lazy[T]) Cid() cid.Cid {
+	return l.root
+}
+
+// IsLoaded returns whether the collection was loaded.
+func (l *lazy[T]) IsLoaded() bool {
+	return l.loaded
+}
+
+// IsDirty returns whether the collection was loaded for modification since the last flush.
+func (l *lazy[T]) IsDirty() bool {
+	return l.dirty
+}
+
+// Flush stores the collection if it was loaded for modification and returns its new root cid.
+func (l *lazy[T]) Flush() (cid.Cid, error) {
+	if !l.dirty {
+		return l.root, nil
+	}
+	c, err := l.value.Root()
+	if err != nil {
+		return cid.Undef, err
+	}
+	l.root = c
+	l.dirty = false
+	return c, nil
+}
+
+// MarshalCBOR flushes the collection and writes its root cid.
+func (l *lazy[T]) MarshalCBOR(w io.Writer) error {
+	c, err := l.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to flush lazy field: %w", err)
+	}
+	return cbg.WriteCid(w, c)
+}
+
+// UnmarshalCBOR reads the root cid of the collection, it's loaded on first use.
+func (l *lazy[T]) UnmarshalCBOR(r io.Reader) error {
+	c, err := cbg.ReadCid(r)
+	if err != nil {
+		return fmt.Errorf("failed to read cid field: %w", err)
+	}
+	*l = lazy[T]{root: c}
+	return nil
+}
+
+func (l *lazy[T]) get(load func(root cid.Cid) (T, error), mutable bool) (T, error) {
+	if !l.loaded {
+		value, err := load(l.root)
+		if err != nil {
+			return value, err
+		}
+		l.value = value
+		l.loaded = true
+	}
+	if mutable {
+		l.dirty = true
+	}
+	return l.value, nil
+}
+
+func (l *lazy[T]) set(value T) {
+	l.value = value
+	l.loaded = true
+	l.dirty = true
+}
+
+// LazyMap a state field holding a Map, loaded on first use.
+type LazyMap struct {
+	lazy[*Map]
+}
+
+// NewLazyMap wraps a new map, eg. in an actor constructor.
+func NewLazyMap(m *Map) LazyMap {
+	var l LazyMap
+	l.set(m)
+	return l
+}
+
+// Load returns the map for modification, it's stored when the state is saved.
+func (l *LazyMap) Load(s Store, bitwidth int) (*Map, error) {
+	return l.get(func(root cid.Cid) (*Map, error) { return AsMap(s, root, bitwidth) }, true)
+}
+
+// View returns the map for reading, it is only stored if it is also loaded by Load.
+func (l *LazyMap) View(s Store, bitwidth int) (*Map, error) {
+	return l.get(func(root cid.Cid) (*Map, error) { return AsMap(s, root, bitwidth) }, false)
+}
+
+// LazyArray a state field holding an Array, loaded on first use.
+type LazyArray struct {
+	lazy[*Array]
+}
+
+// NewLazyArray wraps a new array, eg. in an actor constructor.
+func NewLazyArray(a *Array) LazyArray {
+	var l LazyArray
+	l.set(a)
+	return l
+}
+
+// Load returns the array for modification, it's stored when the state is saved.
+func (l *LazyArray) Load(s Store, bitwidth int) (*Array, error) {
+	return l.get(func(root cid.Cid) (*Array, error) { return AsArray(s, root, bitwidth) }, true)
+}
+
+// View returns the array for reading, it is only stored if it is also loaded by Load.
+func (l *LazyArray) View(s Store, bitwidth int) (*Array, error) {
+	return l.get(func(root cid.Cid) (*Array, error) { return AsArray(s, root, bitwidth) }, false)
+}
+
+// LazySet a state field holding a Set, loaded on first use.
+type LazySet struct {
+	lazy[*Set]
+}
+
+// NewLazySet wraps a new set, eg. in an actor constructor.
+func NewLazySet(set *Set) LazySet {
+	var l LazySet
+	l.set(set)
+	return l
+}
+
+// Load returns the set for modification, it's stored when the state is saved.
+func (l *LazySet) Load(s Store, bitwidth int) (*Set, error) {
+	return l.get(func(root cid.Cid) (*Set, error) { return AsSet(s, root, bitwidth) }, true)
+}
+
+// View returns the set for reading, it is only stored if it is also loaded by Load.
+func (l *LazySet) View(s Store, bitwidth int) (*Set, error) {
+	return l.get(func(root cid.Cid) (*Set, error) { return AsSet(s, root, bitwidth) }, false)
+}
+
+// LazyMultimap a state field holding a Multimap, loaded on first use.
+type LazyMultimap struct {
+	lazy[*Multimap]
+}
+
+// NewLazyMultimap wraps a new multimap, eg. in an actor constructor.
+func NewLazyMultimap(mm *Multimap) LazyMultimap {
+	var l LazyMultimap
+	l.set(mm)
+	return l
+}
+
+// Load returns the multimap for modification, it's stored when the state is saved.
+func (l *LazyMultimap) Load(s Store, outerBitwidth, innerBitwidth int) (*Multimap, error) {
+	return l.get(func(root cid.Cid) (*Multimap, error) {
+		return AsMultimap(s, root, outerBitwidth, innerBitwidth)
+	}, true)
+}
+
+// View returns the multimap for reading, it is only stored if it is also loaded by Load.
+func (l *LazyMultimap) View(s Store, outerBitwidth, innerBitwidth int) (*Multimap, error) {
+	return l.get(func(root cid.Cid) (*Multimap, error) {
+		return AsMultimap(s, root, outerBitwidth, innerBitwidth)
+	}, false)
+}
+
+// LazyBalanceTable a state field holding a BalanceTable, loaded on first use.
+type LazyBalanceTable struct {
+	lazy[*BalanceTable]
+}
+
+// NewLazyBalanceTable wraps a new balance table, eg. in an actor constructor.
+func NewLazyBalanceTable(t *BalanceTable) LazyBalanceTable {
+	var l LazyBalanceTable
+	l.set(t)
+	return l
+}
+
+// Load returns the balance table for modification, it's stored when the state is saved.
+func (l *LazyBalanceTable) Load(s Store) (*BalanceTable, error) {
+	return l.get(func(root cid.Cid) (*BalanceTable, error) { return AsBalanceTable(s, root) }, true)
+}
+
+// View returns the balance table for reading, it is only stored if it is also loaded by Load.
+func (l *LazyBalanceTable) View(s Store) (*BalanceTable, error) {
+	return l.get(func(root cid.Cid) (*BalanceTable, error) { return AsBalanceTable(s, root) }, false)
+}
@@ -0,0 +1,93 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestLazyMap(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	m, err := MakeEmptyMap(store, 5)
+	assert.Nil(t, err)
+	field := NewLazyMap(m)
+	assert.True(t, field.IsDirty())
+
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, field.MarshalCBOR(buf))
+	assert.False(t, field.IsDirty())
+	emptyRoot := field.Cid()
+	assert.True(t, emptyRoot.Defined())
+
+	// a decoded field is only loaded on first use
+	var decoded LazyMap
+	assert.Nil(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, emptyRoot, decoded.Cid())
+	assert.False(t, decoded.IsLoaded())
+
+	// viewing doesn't mark the field dirty, so it's written back with the same root
+	view, err := decoded.View(store, 5)
+	assert.Nil(t, err)
+	assert.True(t, decoded.IsLoaded())
+	assert.False(t, decoded.IsDirty())
+	found, err := view.Get(abi.IntKey(1), nil)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	loaded, err := decoded.Load(store, 5)
+	assert.Nil(t, err)
+	assert.True(t, decoded.IsDirty())
+	value := cbg.CborInt(42)
+	assert.Nil(t, loaded.Put(abi.IntKey(1), &value))
+
+	buf.Reset()
+	assert.Nil(t, decoded.MarshalCBOR(buf))
+	assert.False(t, decoded.IsDirty())
+	assert.NotEqual(t, emptyRoot, decoded.Cid())
+
+	var reloaded LazyMap
+	assert.Nil(t, reloaded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+	view, err = reloaded.View(store, 5)
+	assert.Nil(t, err)
+	var out cbg.CborInt
+	found, err = view.Get(abi.IntKey(1), &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, value, out)
+}
+
+func TestLazyArrayFlush(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	root, err := StoreEmptyArray(store, 3)
+	assert.Nil(t, err)
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, cbg.WriteCid(buf, root))
+
+	var field LazyArray
+	assert.Nil(t, field.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+	c, err := field.Flush()
+	assert.Nil(t, err)
+	assert.Equal(t, root, c)
+	assert.False(t, field.IsLoaded())
+
+	array, err := field.Load(store, 3)
+	assert.Nil(t, err)
+	value := cbg.CborInt(7)
+	assert.Nil(t, array.AppendContinuous(&value))
+	c, err = field.Flush()
+	assert.Nil(t, err)
+	assert.NotEqual(t, root, c)
+	assert.Equal(t, c, field.Cid())
+	assert.False(t, field.IsDirty())
+}
@@ -24,42 +24,66 @@ var DefaultHamtOptions = []hamt.Option{
 
 // Map stores key-value pairs in a HAMT.
 type Map struct {
-	lastCid cid.Cid
-	root    *hamt.Node
-	store   Store
+	lastCid  cid.Cid
+	root     *hamt.Node
+	store    Store
+	bitwidth int
+	// extra the hamt options passed after the default ones, if any
+	extra []hamt.Option
 }
 
 // AsMap interprets a store as a HAMT-based map with root `r`.
 // The HAMT is interpreted with branching factor 2^bitwidth.
 // We could drop this parameter if https://github.com/filecoin-project/go-hamt-ipld/issues/79 is implemented.
 func AsMap(s Store, root cid.Cid, bitwidth int) (*Map, error) {
-	options := append(DefaultHamtOptions, hamt.UseTreeBitWidth(bitwidth))
-	nd, err := hamt.LoadNode(s.Context(), s, root, options...)
+	return AsMapWithOptions(s, root, bitwidth)
+}
+
+// AsMapWithOptions is AsMap with extra hamt options applied after the default ones, eg. to load a map
+// created by MakeEmptyMapWithOptions with another hash function.
+func AsMapWithOptions(s Store, root cid.Cid, bitwidth int, extra ...hamt.Option) (*Map, error) {
+	nd, err := hamt.LoadNode(s.Context(), s, root, hamtOptions(bitwidth, extra)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load hamt node: %w", err)
 	}
 
 	return &Map{
-		lastCid: root,
-		root:    nd,
-		store:   s,
+		lastCid:  root,
+		root:     nd,
+		store:    s,
+		bitwidth: bitwidth,
+		extra:    extra,
 	}, nil
 }
 
 // MakeEmptyMap creates a new map backed by an empty HAMT.
 func MakeEmptyMap(s Store, bitwidth int) (*Map, error) {
-	options := append(DefaultHamtOptions, hamt.UseTreeBitWidth(bitwidth))
-	nd, err := hamt.NewNode(s, options...)
+	return MakeEmptyMapWithOptions(s, bitwidth)
+}
+
+// MakeEmptyMapWithOptions is MakeEmptyMap with extra hamt options applied after the default ones. The same
+// options must be passed to AsMapWithOptions to load the map. Such a map can't be proven by Prove.
+func MakeEmptyMapWithOptions(s Store, bitwidth int, extra ...hamt.Option) (*Map, error) {
+	nd, err := hamt.NewNode(s, hamtOptions(bitwidth, extra)...)
 	if err != nil {
 		return nil, err
 	}
 	return &Map{
-		lastCid: cid.Undef,
-		root:    nd,
-		store:   s,
+		lastCid:  cid.Undef,
+		root:     nd,
+		store:    s,
+		bitwidth: bitwidth,
+		extra:    extra,
 	}, nil
 }
 
+func hamtOptions(bitwidth int, extra []hamt.Option) []hamt.Option {
+	options := make([]hamt.Option, 0, len(DefaultHamtOptions)+len(extra)+1)
+	options = append(options, DefaultHamtOptions...)
+	options = append(options, hamt.UseTreeBitWidth(bitwidth))
+	return append(options, extra...)
+}
+
 // StoreEmptyMap creates and stores a new empty map, returning its CID.
 func StoreEmptyMap(s Store, bitwidth int) (cid.Cid, error) {
 	m, err := MakeEmptyMap(s, bitwidth)
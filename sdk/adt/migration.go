@@ -0,0 +1,256 @@
+package adt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// errBatchFull stops the iteration once a batch has been collected.
+var errBatchFull = errors.New("batch full")
+
+// MigrationCursor the progress of a migration, save it in actor state between messages to migrate large
+// collections over several messages.
+// Entries are moved from Source to Dest, so both roots must be read during the migration to look up an
+// entry, Source is empty when the migration is done.
+type MigrationCursor struct {
+	Source   cid.Cid
+	Dest     cid.Cid
+	Migrated uint64
+	Done     bool
+}
+
+// MigrationBudget limits the work done by a migration step.
+type MigrationBudget struct {
+	// MaxEntries max number of entries moved in one step, zero for unlimited.
+	MaxEntries int
+	// MinGas the step stops once the available gas drops below it, zero to skip the gas check.
+	// At least one entry is moved by each step so the migration always makes progress.
+	MinGas uint64
+}
+
+// checkGas stops the collection of a batch with errBatchFull once the available gas is below MinGas.
+func (b MigrationBudget) checkGas(s Store) error {
+	if b.MinGas == 0 {
+		return nil
+	}
+	if gas, err := sdk.AvailableGas(s.Context()); err != nil || gas < b.MinGas {
+		return errBatchFull
+	}
+	return nil
+}
+
+// MapMigration moves the entries of a Map into a new Map with another bitwidth, hash function or key
+// encoding.
+type MapMigration struct {
+	FromBitwidth int
+	ToBitwidth   int
+	// FromOptions and ToOptions are applied after the default hamt options, eg. hamt.UseHashFunction
+	// to change the hash function. Load the migrated map with AsMapWithOptions and ToOptions.
+	FromOptions []hamt.Option
+	ToOptions   []hamt.Option
+	// Transform returns the key and value stored in the new map for an entry of the old one, a nil value
+	// drops the entry. Entries are copied unchanged if it's nil.
+	Transform func(key string, value *cbg.Deferred) (abi.Keyer, cbor.Marshaler, error)
+	Budget    MigrationBudget
+}
+
+// Start creates the cursor of a migration of the map with root `source`.
+func (mig *MapMigration) Start(s Store, source cid.Cid) (*MigrationCursor, error) {
+	dest, err := MakeEmptyMapWithOptions(s, mig.ToBitwidth, mig.ToOptions...)
+	if err != nil {
+		return nil, err
+	}
+	destRoot, err := dest.Root()
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationCursor{Source: source, Dest: destRoot}, nil
+}
+
+// Step moves a batch of entries within the budget and updates the cursor. Moved entries are deleted from
+// the source map, the map at the original source root is left intact but the source of the cursor is
+// consumed by the migration.
+func (mig *MapMigration) Step(s Store, cursor *MigrationCursor) error {
+	if cursor.Done {
+		return nil
+	}
+	source, err := AsMapWithOptions(s, cursor.Source, mig.FromBitwidth, mig.FromOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to load migration source: %w", err)
+	}
+	dest, err := AsMapWithOptions(s, cursor.Dest, mig.ToBitwidth, mig.ToOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to load migration destination: %w", err)
+	}
+
+	type entry struct {
+		key   string
+		value cbg.Deferred
+	}
+	var batch []entry
+	var value cbg.Deferred
+	err = source.ForEach(&value, func(key string) error {
+		// the raw buffer of value is reused by the next entry
+		batch = append(batch, entry{key: key, value: cbg.Deferred{Raw: append([]byte(nil), value.Raw...)}})
+		if mig.Budget.MaxEntries > 0 && len(batch) >= mig.Budget.MaxEntries {
+			return errBatchFull
+		}
+		return mig.Budget.checkGas(s)
+	})
+	if err != nil && !errors.Is(err, errBatchFull) {
+		return err
+	}
+
+	for i := range batch {
+		e := &batch[i]
+		var key abi.Keyer = types.StringKey(e.key)
+		var val cbor.Marshaler = &e.value
+		if mig.Transform != nil {
+			if key, val, err = mig.Transform(e.key, &e.value); err != nil {
+				return fmt.Errorf("failed to transform key %x: %w", e.key, err)
+			}
+		}
+		if val != nil {
+			if err := dest.Put(key, val); err != nil {
+				return err
+			}
+		}
+		if err := source.Delete(types.StringKey(e.key)); err != nil {
+			return err
+		}
+		cursor.Migrated++
+
+		if i+1 < len(batch) && mig.Budget.checkGas(s) != nil {
+			break
+		}
+	}
+
+	cursor.Done = source.IsEmpty()
+	if cursor.Source, err = source.Root(); err != nil {
+		return err
+	}
+	cursor.Dest, err = dest.Root()
+	return err
+}
+
+// Run migrates the map with root `source` at once, ignoring the budget, and returns the new root.
+func (mig *MapMigration) Run(s Store, source cid.Cid) (cid.Cid, error) {
+	cursor, err := mig.Start(s, source)
+	if err != nil {
+		return cid.Undef, err
+	}
+	unlimited := *mig
+	unlimited.Budget = MigrationBudget{}
+	if err := unlimited.Step(s, cursor); err != nil {
+		return cid.Undef, err
+	}
+	return cursor.Dest, nil
+}
+
+// ArrayMigration moves the entries of an Array into a new Array with another bitwidth or index layout.
+type ArrayMigration struct {
+	FromBitwidth int
+	ToBitwidth   int
+	// Transform returns the index and value stored in the new array for an entry of the old one, a nil
+	// value drops the entry. Entries are copied unchanged if it's nil.
+	Transform func(i uint64, value *cbg.Deferred) (uint64, cbor.Marshaler, error)
+	Budget    MigrationBudget
+}
+
+// Start creates the cursor of a migration of the array with root `source`.
+func (mig *ArrayMigration) Start(s Store, source cid.Cid) (*MigrationCursor, error) {
+	destRoot, err := StoreEmptyArray(s, mig.ToBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationCursor{Source: source, Dest: destRoot}, nil
+}
+
+// Step moves a batch of entries within the budget and updates the cursor. Moved entries are deleted from
+// the source array, the array at the original source root is left intact but the source of the cursor is
+// consumed by the migration.
+func (mig *ArrayMigration) Step(s Store, cursor *MigrationCursor) error {
+	if cursor.Done {
+		return nil
+	}
+	source, err := AsArray(s, cursor.Source, mig.FromBitwidth)
+	if err != nil {
+		return fmt.Errorf("failed to load migration source: %w", err)
+	}
+	dest, err := AsArray(s, cursor.Dest, mig.ToBitwidth)
+	if err != nil {
+		return fmt.Errorf("failed to load migration destination: %w", err)
+	}
+
+	type entry struct {
+		index uint64
+		value cbg.Deferred
+	}
+	var batch []entry
+	var value cbg.Deferred
+	err = source.ForEach(&value, func(i int64) error {
+		batch = append(batch, entry{index: uint64(i), value: cbg.Deferred{Raw: append([]byte(nil), value.Raw...)}})
+		if mig.Budget.MaxEntries > 0 && len(batch) >= mig.Budget.MaxEntries {
+			return errBatchFull
+		}
+		return mig.Budget.checkGas(s)
+	})
+	if err != nil && !errors.Is(err, errBatchFull) {
+		return err
+	}
+
+	moved := make([]uint64, 0, len(batch))
+	for i := range batch {
+		e := &batch[i]
+		index := e.index
+		var val cbor.Marshaler = &e.value
+		if mig.Transform != nil {
+			if index, val, err = mig.Transform(e.index, &e.value); err != nil {
+				return fmt.Errorf("failed to transform index %d: %w", e.index, err)
+			}
+		}
+		if val != nil {
+			if err := dest.Set(index, val); err != nil {
+				return err
+			}
+		}
+		moved = append(moved, e.index)
+		cursor.Migrated++
+
+		if i+1 < len(batch) && mig.Budget.checkGas(s) != nil {
+			break
+		}
+	}
+	if err := source.BatchDelete(moved, true); err != nil {
+		return err
+	}
+
+	cursor.Done = source.Length() == 0
+	if cursor.Source, err = source.Root(); err != nil {
+		return err
+	}
+	cursor.Dest, err = dest.Root()
+	return err
+}
+
+// Run migrates the array with root `source` at once, ignoring the budget, and returns the new root.
+func (mig *ArrayMigration) Run(s Store, source cid.Cid) (cid.Cid, error) {
+	cursor, err := mig.Start(s, source)
+	if err != nil {
+		return cid.Undef, err
+	}
+	unlimited := *mig
+	unlimited.Budget = MigrationBudget{}
+	if err := unlimited.Step(s, cursor); err != nil {
+		return cid.Undef, err
+	}
+	return cursor.Dest, nil
+}
@@ -0,0 +1,157 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/minio/sha256-simd"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestMapMigration(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	m, err := MakeEmptyMap(store, 5)
+	assert.Nil(t, err)
+	for i := 0; i < 100; i++ {
+		val := big.NewInt(int64(i))
+		assert.Nil(t, m.Put(types.ActorKey(i), &val))
+	}
+	root, err := m.Root()
+	assert.Nil(t, err)
+
+	mig := &MapMigration{
+		FromBitwidth: 5,
+		ToBitwidth:   BalanceTableBitwidth,
+		Transform: func(key string, value *cbg.Deferred) (abi.Keyer, cbor.Marshaler, error) {
+			// drop zero, double the others
+			var val abi.TokenAmount
+			if err := val.UnmarshalCBOR(bytes.NewReader(value.Raw)); err != nil {
+				return nil, nil, err
+			}
+			if val.IsZero() {
+				return nil, nil, nil
+			}
+			doubled := big.Mul(val, big.NewInt(2))
+			return types.StringKey("k" + key), &doubled, nil
+		},
+		Budget: MigrationBudget{MaxEntries: 30},
+	}
+	cursor, err := mig.Start(store, root)
+	assert.Nil(t, err)
+	steps := 0
+	for !cursor.Done {
+		if !assert.Nil(t, mig.Step(store, cursor)) {
+			break
+		}
+		steps++
+	}
+	assert.Equal(t, 4, steps)
+	assert.Equal(t, uint64(100), cursor.Migrated)
+
+	report := CheckMap(store, cursor.Dest, BalanceTableBitwidth, newTokenAmount)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(99), report.Entries)
+
+	dest, err := AsMap(store, cursor.Dest, BalanceTableBitwidth)
+	assert.Nil(t, err)
+	var val abi.TokenAmount
+	found, err := dest.Get(types.StringKey("k7"), &val)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, big.NewInt(14), val)
+}
+
+func TestMapMigrationGasBudget(t *testing.T) {
+	// the simulator reports no available gas, every step stops after the first entry
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	m, err := MakeEmptyMap(store, 5)
+	assert.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		val := big.NewInt(int64(i))
+		assert.Nil(t, m.Put(types.ActorKey(i), &val))
+	}
+	root, err := m.Root()
+	assert.Nil(t, err)
+
+	mig := &MapMigration{
+		FromBitwidth: 5,
+		ToBitwidth:   5,
+		ToOptions: []hamt.Option{hamt.UseHashFunction(func(input []byte) []byte {
+			res := sha256.Sum256(append([]byte("v2"), input...))
+			return res[:]
+		})},
+		Budget: MigrationBudget{MinGas: 1},
+	}
+	cursor, err := mig.Start(store, root)
+	assert.Nil(t, err)
+	for steps := 1; !cursor.Done; steps++ {
+		assert.Nil(t, mig.Step(store, cursor))
+		assert.Equal(t, uint64(steps), cursor.Migrated)
+	}
+	assert.Equal(t, uint64(5), cursor.Migrated)
+
+	dest, err := AsMapWithOptions(store, cursor.Dest, 5, mig.ToOptions...)
+	assert.Nil(t, err)
+	var val abi.TokenAmount
+	found, err := dest.Get(types.ActorKey(3), &val)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, big.NewInt(3), val)
+
+	// the default hash function can't find the keys
+	dest, err = AsMap(store, cursor.Dest, 5)
+	assert.Nil(t, err)
+	found, err = dest.Get(types.ActorKey(3), &val)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func TestArrayMigration(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	arr, err := MakeEmptyArray(store, 3)
+	assert.Nil(t, err)
+	for i := uint64(0); i < 50; i++ {
+		val := big.NewInt(int64(i))
+		assert.Nil(t, arr.Set(i*3, &val))
+	}
+	root, err := arr.Root()
+	assert.Nil(t, err)
+
+	mig := &ArrayMigration{
+		FromBitwidth: 3,
+		ToBitwidth:   5,
+		Transform: func(i uint64, value *cbg.Deferred) (uint64, cbor.Marshaler, error) {
+			return i / 3, value, nil
+		},
+	}
+	newRoot, err := mig.Run(store, root)
+	assert.Nil(t, err)
+
+	report := CheckArray(store, newRoot, 5, newTokenAmount)
+	assert.Nil(t, report.Err())
+	assert.Equal(t, uint64(50), report.Entries)
+
+	dest, err := AsArray(store, newRoot, 5)
+	assert.Nil(t, err)
+	var val abi.TokenAmount
+	found, err := dest.Get(10, &val)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, big.NewInt(10), val)
+}
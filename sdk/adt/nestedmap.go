@@ -0,0 +1,201 @@
+package adt
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// NestedMap stores key-value pairs in two levels of HAMTs, eg. Map<Owner, Map<Operator, TokenAmount>>.
+// The outer map stores the root cid of an inner map for every outer key. Inner maps are loaded on demand
+// and written back to the outer map by Root, inner maps left empty are removed from the outer map.
+type NestedMap struct {
+	outer         *Map
+	innerBitwidth int
+	// inner maps loaded so far, by outer key
+	inner map[string]*Map
+	// outer keys of inner maps modified since the last Root
+	dirty map[string]struct{}
+}
+
+// AsNestedMap interprets a store as a nested map with root `r`.
+// The outer and inner HAMTs have branching factor of 2^outerBitwidth and 2^innerBitwidth.
+func AsNestedMap(s Store, r cid.Cid, outerBitwidth, innerBitwidth int) (*NestedMap, error) {
+	m, err := AsMap(s, r, outerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return newNestedMap(m, innerBitwidth), nil
+}
+
+// MakeEmptyNestedMap creates a new nested map backed by an empty HAMT.
+func MakeEmptyNestedMap(s Store, outerBitwidth, innerBitwidth int) (*NestedMap, error) {
+	m, err := MakeEmptyMap(s, outerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return newNestedMap(m, innerBitwidth), nil
+}
+
+// StoreEmptyNestedMap creates and stores a new empty nested map, returning its CID.
+func StoreEmptyNestedMap(s Store, outerBitwidth, innerBitwidth int) (cid.Cid, error) {
+	nm, err := MakeEmptyNestedMap(s, outerBitwidth, innerBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return nm.Root()
+}
+
+func newNestedMap(m *Map, innerBitwidth int) *NestedMap {
+	return &NestedMap{
+		outer:         m,
+		innerBitwidth: innerBitwidth,
+		inner:         make(map[string]*Map),
+		dirty:         make(map[string]struct{}),
+	}
+}
+
+// Root flushes the modified inner maps into the outer map and returns the root cid of the outer HAMT.
+// Inner maps are flushed in the order of their outer keys so the gas charged doesn't depend on the map
+// iteration order.
+func (nm *NestedMap) Root() (cid.Cid, error) {
+	keys := make([]string, 0, len(nm.dirty))
+	for key := range nm.dirty {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		inner := nm.inner[key]
+		if inner.IsEmpty() {
+			if _, err := nm.outer.TryDelete(types.StringKey(key)); err != nil {
+				return cid.Undef, fmt.Errorf("failed to remove empty inner map %v: %w", key, err)
+			}
+			continue
+		}
+		c, err := inner.Root()
+		if err != nil {
+			return cid.Undef, fmt.Errorf("failed to flush inner map %v: %w", key, err)
+		}
+		innerRoot := cbg.CborCid(c)
+		if err := nm.outer.Put(types.StringKey(key), &innerRoot); err != nil {
+			return cid.Undef, fmt.Errorf("failed to store inner map %v: %w", key, err)
+		}
+	}
+	nm.dirty = make(map[string]struct{})
+	return nm.outer.Root()
+}
+
+// Get retrieves the value at `inner` of the inner map at `outer` into `out`, if it's present and `out`
+// is non-nil. Returns whether the key was found.
+func (nm *NestedMap) Get(outer, inner abi.Keyer, out cbor.Unmarshaler) (bool, error) {
+	m, found, err := nm.load(outer, false)
+	if err != nil || !found {
+		return false, err
+	}
+	return m.Get(inner, out)
+}
+
+// Put adds value `v` with key `inner` to the inner map at `outer`, creating the inner map if needed.
+func (nm *NestedMap) Put(outer, inner abi.Keyer, v cbor.Marshaler) error {
+	m, _, err := nm.load(outer, true)
+	if err != nil {
+		return err
+	}
+	if err := m.Put(inner, v); err != nil {
+		return err
+	}
+	nm.dirty[outer.Key()] = struct{}{}
+	return nil
+}
+
+// TryDelete removes the value at `inner` from the inner map at `outer`, if it exists. The inner map is
+// removed from the outer map by Root once it's empty.
+// Returns whether the key was previously present.
+func (nm *NestedMap) TryDelete(outer, inner abi.Keyer) (bool, error) {
+	m, found, err := nm.load(outer, false)
+	if err != nil || !found {
+		return false, err
+	}
+	found, err = m.TryDelete(inner)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		nm.dirty[outer.Key()] = struct{}{}
+	}
+	return found, nil
+}
+
+// Delete removes the value at `inner` from the inner map at `outer`, expecting it to exist.
+func (nm *NestedMap) Delete(outer, inner abi.Keyer) error {
+	found, err := nm.TryDelete(outer, inner)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no key %v in inner map %v to delete", inner.Key(), outer.Key())
+	}
+	return nil
+}
+
+// RemoveAll removes the whole inner map at `outer`.
+func (nm *NestedMap) RemoveAll(outer abi.Keyer) error {
+	delete(nm.inner, outer.Key())
+	delete(nm.dirty, outer.Key())
+	if _, err := nm.outer.TryDelete(outer); err != nil {
+		return fmt.Errorf("failed to delete inner map %v: %w", outer.Key(), err)
+	}
+	return nil
+}
+
+// ForEach iterates all entries of the inner map at `outer`, deserializing each value in turn into `out`
+// and then calling a function with the corresponding inner key.
+// Iteration halts if the function returns an error.
+// If the output parameter is nil, deserialization is skipped.
+func (nm *NestedMap) ForEach(outer abi.Keyer, out cbor.Unmarshaler, fn func(key string) error) error {
+	m, found, err := nm.load(outer, false)
+	if err != nil || !found {
+		return err
+	}
+	return m.ForEach(out, fn)
+}
+
+// ForEachOuter iterates all outer keys which have a non-empty inner map, as of the last Root.
+// Iteration halts if the function returns an error.
+func (nm *NestedMap) ForEachOuter(fn func(key string) error) error {
+	return nm.outer.ForEach(nil, fn)
+}
+
+// load returns the inner map at `outer`, an empty one is created if it's absent and `create` is true.
+func (nm *NestedMap) load(outer abi.Keyer, create bool) (*Map, bool, error) {
+	key := outer.Key()
+	if m, ok := nm.inner[key]; ok {
+		return m, true, nil
+	}
+
+	var innerRoot cbg.CborCid
+	found, err := nm.outer.Get(outer, &innerRoot)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load nested map key %v: %w", key, err)
+	}
+
+	var m *Map
+	switch {
+	case found:
+		m, err = AsMap(nm.outer.store, cid.Cid(innerRoot), nm.innerBitwidth)
+	case create:
+		m, err = MakeEmptyMap(nm.outer.store, nm.innerBitwidth)
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load inner map %v: %w", key, err)
+	}
+	nm.inner[key] = m
+	return m, true, nil
+}
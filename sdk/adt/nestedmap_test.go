@@ -0,0 +1,49 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestedMap(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	nm, err := MakeEmptyNestedMap(store, 3, 3)
+	assert.Nil(t, err)
+	emptyRoot, err := nm.Root()
+	assert.Nil(t, err)
+
+	allowance := big.NewInt(100)
+	assert.Nil(t, nm.Put(types.ActorKey(1), types.ActorKey(2), &allowance))
+	assert.Nil(t, nm.Put(types.ActorKey(1), types.ActorKey(3), &allowance))
+	root, err := nm.Root()
+	assert.Nil(t, err)
+
+	loaded, err := AsNestedMap(store, root, 3, 3)
+	assert.Nil(t, err)
+	var val big.Int
+	found, err := loaded.Get(types.ActorKey(1), types.ActorKey(3), &val)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, allowance, val)
+	found, err = loaded.Get(types.ActorKey(2), types.ActorKey(3), &val)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	assert.Nil(t, loaded.Delete(types.ActorKey(1), types.ActorKey(2)))
+	assert.Nil(t, loaded.Delete(types.ActorKey(1), types.ActorKey(3)))
+	assert.NotNil(t, loaded.Delete(types.ActorKey(1), types.ActorKey(3)))
+
+	// empty inner map is removed from the outer map
+	root, err = loaded.Root()
+	assert.Nil(t, err)
+	assert.Equal(t, emptyRoot, root)
+}
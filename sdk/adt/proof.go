@@ -0,0 +1,159 @@
+package adt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	"github.com/minio/sha256-simd"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// ErrInvalidProof returned when a proof doesn't match the root it's verified against.
+var ErrInvalidProof = errors.New("invalid hamt proof")
+
+// ErrProofUnsupported returned by Prove for a map built with extra hamt options, eg. another hash function,
+// as proofs follow the default sha256 key hashing.
+var ErrProofUnsupported = errors.New("hamt proof unsupported for maps with extra options")
+
+// MapProof is a merkle proof of inclusion or exclusion of a key in a Map. It holds the raw blocks of
+// the HAMT nodes visited from the root down to the node where the key is (or would be) stored.
+type MapProof struct {
+	Blocks [][]byte
+}
+
+// Prove returns a proof for key `k` against the current root of the map, the map is flushed first
+// so the proof matches the cid returned by Root.
+// The proof can be verified with VerifyMapProof, whether the key is present or not.
+// Returns ErrProofUnsupported for a map created or loaded with extra hamt options.
+func (m *Map) Prove(k abi.Keyer) (*MapProof, error) {
+	if len(m.extra) > 0 {
+		return nil, ErrProofUnsupported
+	}
+	root, err := m.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &MapProof{}
+	_, err = walkHamtPath(root, m.bitwidth, []byte(k.Key()), func(c cid.Cid) ([]byte, error) {
+		var raw cbg.Deferred
+		if err := m.store.Get(m.store.Context(), c, &raw); err != nil {
+			return nil, fmt.Errorf("failed to load hamt node %v: %w", c, err)
+		}
+		proof.Blocks = append(proof.Blocks, raw.Raw)
+		return raw.Raw, nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// VerifyMapProof checks the proof against the `root` of a Map with the given bitwidth and the default hamt
+// options, and decodes the value of key `k` into `out` if it's non-nil and the key is present.
+// Returns whether the key is present, or ErrInvalidProof if the proof doesn't match the root.
+// It doesn't use any syscall, so it works in actors as well as off-chain.
+func VerifyMapProof(root cid.Cid, bitwidth int, k abi.Keyer, proof *MapProof, out cbor.Unmarshaler) (bool, error) {
+	next := 0
+	found, err := walkHamtPath(root, bitwidth, []byte(k.Key()), func(c cid.Cid) ([]byte, error) {
+		if next >= len(proof.Blocks) {
+			return nil, fmt.Errorf("%w: missing block %v", ErrInvalidProof, c)
+		}
+		block := proof.Blocks[next]
+		next++
+
+		actual, err := c.Prefix().Sum(block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to hash block: %v", ErrInvalidProof, err)
+		}
+		if !actual.Equals(c) {
+			return nil, fmt.Errorf("%w: block %d hashes to %v, expected %v", ErrInvalidProof, next-1, actual, c)
+		}
+		return block, nil
+	}, out)
+	if err != nil {
+		return false, err
+	}
+	if next != len(proof.Blocks) {
+		return false, fmt.Errorf("%w: %d unused blocks", ErrInvalidProof, len(proof.Blocks)-next)
+	}
+	return found, nil
+}
+
+// walkHamtPath follows the path of `key` from the node `root` down to the bucket where it's stored,
+// loading every node on the way with `load`. Returns whether the key was found and decodes the value
+// into `out` if it's non-nil.
+func walkHamtPath(root cid.Cid, bitwidth int, key []byte, load func(c cid.Cid) ([]byte, error), out cbor.Unmarshaler) (bool, error) {
+	digest := sha256.Sum256(key)
+	hb := &hashBits{b: digest[:]}
+
+	next := root
+	for {
+		block, err := load(next)
+		if err != nil {
+			return false, err
+		}
+		var node hamt.Node
+		if err := node.UnmarshalCBOR(bytes.NewReader(block)); err != nil {
+			return false, fmt.Errorf("%w: failed to decode hamt node %v: %v", ErrInvalidProof, next, err)
+		}
+
+		idx, err := hb.next(bitwidth)
+		if err != nil {
+			return false, err
+		}
+		if node.Bitfield.Bit(idx) == 0 {
+			return false, nil
+		}
+
+		cindex := 0
+		for i := 0; i < idx; i++ {
+			cindex += int(node.Bitfield.Bit(i))
+		}
+		if cindex >= len(node.Pointers) {
+			return false, fmt.Errorf("%w: hamt node %v has no pointer at %d", ErrInvalidProof, next, cindex)
+		}
+
+		pointer := node.Pointers[cindex]
+		if pointer.Link.Defined() {
+			next = pointer.Link
+			continue
+		}
+		for _, kv := range pointer.KVs {
+			if bytes.Equal(kv.Key, key) {
+				if out != nil {
+					if err := out.UnmarshalCBOR(bytes.NewReader(kv.Value.Raw)); err != nil {
+						return false, err
+					}
+				}
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// hashBits reads the digest of a key `bitwidth` bits at a time from the most significant bit, the same
+// way as go-hamt-ipld.
+type hashBits struct {
+	b        []byte
+	consumed int
+}
+
+func (hb *hashBits) next(i int) (int, error) {
+	if hb.consumed+i > len(hb.b)*8 {
+		return 0, fmt.Errorf("hamt too deep")
+	}
+	out := 0
+	for j := 0; j < i; j++ {
+		bit := (hb.b[hb.consumed/8] >> (7 - uint(hb.consumed%8))) & 1
+		out = out<<1 | int(bit)
+		hb.consumed++
+	}
+	return out, nil
+}
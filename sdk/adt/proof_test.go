@@ -0,0 +1,81 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/minio/sha256-simd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapProof(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+
+	m, err := MakeEmptyMap(AdtStore(ctx), BalanceTableBitwidth)
+	assert.Nil(t, err)
+	for i := 0; i < 500; i++ {
+		val := big.NewInt(int64(i))
+		assert.Nil(t, m.Put(types.ActorKey(i), &val))
+	}
+	root, err := m.Root()
+	assert.Nil(t, err)
+
+	t.Run("inclusion", func(t *testing.T) {
+		proof, err := m.Prove(types.ActorKey(321))
+		assert.Nil(t, err)
+		assert.True(t, len(proof.Blocks) > 1)
+
+		// proof survives a cbor round trip
+		buf := bytes.NewBuffer(nil)
+		assert.Nil(t, proof.MarshalCBOR(buf))
+		var decoded MapProof
+		assert.Nil(t, decoded.UnmarshalCBOR(buf))
+
+		var val big.Int
+		found, err := VerifyMapProof(root, BalanceTableBitwidth, types.ActorKey(321), &decoded, &val)
+		assert.Nil(t, err)
+		assert.True(t, found)
+		assert.Equal(t, int64(321), val.Int64())
+	})
+
+	t.Run("exclusion", func(t *testing.T) {
+		proof, err := m.Prove(types.ActorKey(100000))
+		assert.Nil(t, err)
+		found, err := VerifyMapProof(root, BalanceTableBitwidth, types.ActorKey(100000), proof, nil)
+		assert.Nil(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		proof, err := m.Prove(types.ActorKey(321))
+		assert.Nil(t, err)
+
+		// proof for another key
+		_, err = VerifyMapProof(root, BalanceTableBitwidth, types.ActorKey(7), proof, nil)
+		assert.ErrorIs(t, err, ErrInvalidProof)
+
+		last := proof.Blocks[len(proof.Blocks)-1]
+		last[len(last)-1] ^= 1
+		_, err = VerifyMapProof(root, BalanceTableBitwidth, types.ActorKey(321), proof, nil)
+		assert.ErrorIs(t, err, ErrInvalidProof)
+	})
+
+	t.Run("extra options", func(t *testing.T) {
+		other, err := MakeEmptyMapWithOptions(AdtStore(ctx), BalanceTableBitwidth, hamt.UseHashFunction(func(input []byte) []byte {
+			res := sha256.Sum256(append([]byte("salt"), input...))
+			return res[:]
+		}))
+		assert.Nil(t, err)
+		val := big.NewInt(1)
+		assert.Nil(t, other.Put(types.ActorKey(1), &val))
+		_, err = other.Prove(types.ActorKey(1))
+		assert.ErrorIs(t, err, ErrProofUnsupported)
+	})
+}
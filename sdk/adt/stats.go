@@ -0,0 +1,208 @@
+package adt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/internal"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// CollectionKind the kind of a collection linked from a state, detected from the shape of its root block.
+type CollectionKind int
+
+const (
+	// KindBlock a plain block, or a collection of unknown kind.
+	KindBlock CollectionKind = iota
+	// KindHamt a HAMT, eg. Map, Set or Multimap.
+	KindHamt
+	// KindAmt an AMT, eg. Array.
+	KindAmt
+)
+
+func (k CollectionKind) String() string {
+	switch k {
+	case KindHamt:
+		return "hamt"
+	case KindAmt:
+		return "amt"
+	default:
+		return "block"
+	}
+}
+
+// StorageStats the storage used by the blocks reachable from a root, each block counted once.
+// Only DAG-CBOR blocks are loaded and counted.
+type StorageStats struct {
+	Blocks uint64
+	Bytes  uint64
+	// Depth the longest chain of links from the root, a root without links has depth 1.
+	Depth int
+}
+
+// CollectionStats the storage used by a collection linked from a field of a state.
+type CollectionStats struct {
+	Name  string
+	Field int
+	Kind  CollectionKind
+	// Entries the number of entries of a HAMT or AMT, entries of nested collections are not included.
+	Entries uint64
+	StorageStats
+}
+
+// StateStats the storage used by a state, and by each collection linked from its fields. Blocks shared by
+// several collections are counted once in the total and once in every collection.
+type StateStats struct {
+	StorageStats
+	Collections []*CollectionStats
+}
+
+// StateSchema names the link fields of a state, a cbor tuple, by field index. Tools and tests can build it
+// from the state struct with adtschema.Of.
+type StateSchema struct {
+	Fields map[int]string
+}
+
+// WalkState reports the storage used by the state with root `root`, with a breakdown for every link field
+// of the root block. Fields are named by `schema`, fields missing from the schema or a nil schema are named
+// by index.
+func WalkState(s Store, root cid.Cid, schema *StateSchema) (*StateStats, error) {
+	stats := &StateStats{}
+	if err := walkStorage(s, root, &stats.StorageStats); err != nil {
+		return nil, err
+	}
+
+	raw, err := loadBlock(s, root)
+	if err != nil {
+		return nil, err
+	}
+	br := bytes.NewReader(raw)
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if maj != cbg.MajArray {
+		// not a tuple, no fields to break down
+		return stats, nil
+	}
+	for i := 0; i < int(extra); i++ {
+		var field cbg.Deferred
+		if err := field.UnmarshalCBOR(br); err != nil {
+			return nil, fmt.Errorf("failed to read state field %d: %w", i, err)
+		}
+		if len(field.Raw) == 0 || field.Raw[0]>>5 != cbg.MajTag {
+			continue
+		}
+		link, err := cbg.ReadCid(bytes.NewReader(field.Raw))
+		if err != nil {
+			continue
+		}
+
+		name := fmt.Sprintf("field%d", i)
+		if schema != nil {
+			if n, ok := schema.Fields[i]; ok {
+				name = n
+			}
+		}
+		collection, err := walkCollection(s, link)
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk state field %s: %w", name, err)
+		}
+		collection.Name = name
+		collection.Field = i
+		stats.Collections = append(stats.Collections, collection)
+	}
+	return stats, nil
+}
+
+// Collection returns the stats of the collection with name `name`, or nil.
+func (stats *StateStats) Collection(name string) *CollectionStats {
+	for _, c := range stats.Collections {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func walkCollection(s Store, root cid.Cid) (*CollectionStats, error) {
+	stats := &CollectionStats{}
+	if err := walkStorage(s, root, &stats.StorageStats); err != nil {
+		return nil, err
+	}
+	if root.Prefix().Codec != types.DAGCBOR {
+		return stats, nil
+	}
+	raw, err := loadBlock(s, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var amt amtRoot
+	br := bytes.NewReader(raw)
+	if amt.unmarshalCBOR(br) == nil && br.Len() == 0 {
+		stats.Kind = KindAmt
+		stats.Entries = amt.count
+		return stats, nil
+	}
+	var node hamt.Node
+	if node.UnmarshalCBOR(bytes.NewReader(raw)) == nil {
+		stats.Kind = KindHamt
+		stats.Entries, err = countHamtEntries(s, &node)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func countHamtEntries(s Store, node *hamt.Node) (uint64, error) {
+	var count uint64
+	for _, p := range node.Pointers {
+		if !p.Link.Defined() {
+			count += uint64(len(p.KVs))
+			continue
+		}
+		raw, err := loadBlock(s, p.Link)
+		if err != nil {
+			return 0, err
+		}
+		var child hamt.Node
+		if err := child.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return 0, fmt.Errorf("failed to decode hamt node %v: %w", p.Link, err)
+		}
+		n, err := countHamtEntries(s, &child)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+func walkStorage(s Store, root cid.Cid, stats *StorageStats) error {
+	// only DAG-CBOR blocks can be loaded through the store
+	dag, err := internal.WalkDag(root, func(c cid.Cid) ([]byte, bool, error) {
+		if c.Prefix().Codec != types.DAGCBOR {
+			return nil, false, nil
+		}
+		raw, err := loadBlock(s, c)
+		return raw, err == nil, err
+	})
+	if err != nil {
+		return err
+	}
+	*stats = StorageStats(dag)
+	return nil
+}
+
+func loadBlock(s Store, c cid.Cid) ([]byte, error) {
+	var raw cbg.Deferred
+	if err := s.Get(s.Context(), c, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load block %v: %w", c, err)
+	}
+	return raw.Raw, nil
+}
@@ -0,0 +1,78 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestWalkState(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	m, err := MakeEmptyMap(store, BalanceTableBitwidth)
+	assert.Nil(t, err)
+	arr, err := MakeEmptyArray(store, 3)
+	assert.Nil(t, err)
+	for i := 0; i < 100; i++ {
+		val := big.NewInt(int64(i + 1))
+		assert.Nil(t, m.Put(types.ActorKey(i), &val))
+		assert.Nil(t, arr.Set(uint64(i), &val))
+	}
+	mapRoot, err := m.Root()
+	assert.Nil(t, err)
+	arrRoot, err := arr.Root()
+	assert.Nil(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, cbg.WriteMajorTypeHeader(buf, cbg.MajArray, 3))
+	assert.Nil(t, cbg.WriteCid(buf, mapRoot))
+	assert.Nil(t, cbg.WriteMajorTypeHeader(buf, cbg.MajUnsignedInt, 100))
+	assert.Nil(t, cbg.WriteCid(buf, arrRoot))
+	root, err := store.Put(ctx, &cbg.Deferred{Raw: buf.Bytes()})
+	assert.Nil(t, err)
+
+	stats, err := WalkState(store, root, &StateSchema{Fields: map[int]string{0: "Balances", 2: "Log"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(stats.Collections))
+
+	balances := stats.Collection("Balances")
+	assert.NotNil(t, balances)
+	assert.Equal(t, KindHamt, balances.Kind)
+	assert.Equal(t, uint64(100), balances.Entries)
+	log := stats.Collection("Log")
+	assert.NotNil(t, log)
+	assert.Equal(t, 2, log.Field)
+	assert.Equal(t, KindAmt, log.Kind)
+	assert.Equal(t, uint64(100), log.Entries)
+
+	assert.Equal(t, balances.Blocks+log.Blocks+1, stats.Blocks)
+	assert.Equal(t, uint64(len(buf.Bytes()))+balances.Bytes+log.Bytes, stats.Bytes)
+	assert.Equal(t, 1+maxInt(balances.Depth, log.Depth), stats.Depth)
+
+	assert.Nil(t, sim.SelfSetRoot(root))
+	simStats, err := sim.StateStats()
+	assert.Nil(t, err)
+	assert.Equal(t, stats.Blocks, simStats.Blocks)
+	assert.Equal(t, stats.Bytes, simStats.Bytes)
+	assert.Equal(t, stats.Depth, simStats.Depth)
+
+	stats, err = WalkState(store, root, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, stats.Collection("field0"))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,324 @@
+package adt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// errFound stops an iteration once the looked up value is found.
+var errFound = errors.New("found")
+
+// TypedSet a Set with keys of type K, keys read from the HAMT are parsed by parseKey, eg. types.ParseActorKey.
+// The layout is the same as Set, so roots of a Set can be read as TypedSet and the other way around.
+type TypedSet[K abi.Keyer] struct {
+	set      *Set
+	parseKey func(string) (K, error)
+}
+
+// AsTypedSet interprets a store as a HAMT-based set of keys of type K with root `r`.
+func AsTypedSet[K abi.Keyer](s Store, r cid.Cid, bitwidth int, parseKey func(string) (K, error)) (*TypedSet[K], error) {
+	set, err := AsSet(s, r, bitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedSet[K]{set: set, parseKey: parseKey}, nil
+}
+
+// MakeEmptyTypedSet creates a new set of keys of type K backed by an empty HAMT.
+func MakeEmptyTypedSet[K abi.Keyer](s Store, bitwidth int, parseKey func(string) (K, error)) (*TypedSet[K], error) {
+	set, err := MakeEmptySet(s, bitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedSet[K]{set: set, parseKey: parseKey}, nil
+}
+
+// Root returns the root cid of the underlying HAMT.
+func (ts *TypedSet[K]) Root() (cid.Cid, error) {
+	return ts.set.Root()
+}
+
+// Put adds `k` to the set.
+func (ts *TypedSet[K]) Put(k K) error {
+	return ts.set.Put(k)
+}
+
+// AddMany adds all `keys` to the set.
+func (ts *TypedSet[K]) AddMany(keys ...K) error {
+	for _, k := range keys {
+		if err := ts.set.Put(k); err != nil {
+			return fmt.Errorf("failed to add key %v: %w", k.Key(), err)
+		}
+	}
+	return nil
+}
+
+// Contains returns true iff `k` is in the set.
+func (ts *TypedSet[K]) Contains(k K) (bool, error) {
+	return ts.set.Has(k)
+}
+
+// TryDelete removes `k` from the set, if present.
+// Returns whether the key was previously present.
+func (ts *TypedSet[K]) TryDelete(k K) (bool, error) {
+	return ts.set.TryDelete(k)
+}
+
+// Delete removes `k` from the set, expecting it to be present.
+func (ts *TypedSet[K]) Delete(k K) error {
+	return ts.set.Delete(k)
+}
+
+// ForEach iterates over all keys in the set.
+// Returning error from the callback stops the iteration.
+func (ts *TypedSet[K]) ForEach(cb func(k K) error) error {
+	return ts.set.ForEach(func(key string) error {
+		k, err := ts.parseKey(key)
+		if err != nil {
+			return err
+		}
+		return cb(k)
+	})
+}
+
+// CollectKeys collects all the keys from the set into a slice.
+func (ts *TypedSet[K]) CollectKeys() ([]K, error) {
+	var out []K
+	err := ts.ForEach(func(k K) error {
+		out = append(out, k)
+		return nil
+	})
+	return out, err
+}
+
+// Len returns the number of keys in the set, all HAMT nodes are loaded.
+func (ts *TypedSet[K]) Len() (uint64, error) {
+	var n uint64
+	err := ts.set.ForEach(func(string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// TypedMultimap a Multimap with keys of type K and values of type V, keys read from the HAMT are parsed by
+// parseKey. The layout is the same as Multimap, so roots of a Multimap can be read as TypedMultimap and the
+// other way around.
+// PV is inferred from V by the constructors, eg. AsTypedMultimap[types.ActorKey, abi.TokenAmount](...).
+type TypedMultimap[K abi.Keyer, V any, PV interface {
+	cbor.Er
+	*V
+}] struct {
+	mm       *Multimap
+	parseKey func(string) (K, error)
+}
+
+// AsTypedMultimap interprets a store as a HAMT-based map of AMTs with root `r`.
+func AsTypedMultimap[K abi.Keyer, V any, PV interface {
+	cbor.Er
+	*V
+}](s Store, r cid.Cid, outerBitwidth, innerBitwidth int, parseKey func(string) (K, error)) (*TypedMultimap[K, V, PV], error) {
+	mm, err := AsMultimap(s, r, outerBitwidth, innerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedMultimap[K, V, PV]{mm: mm, parseKey: parseKey}, nil
+}
+
+// MakeEmptyTypedMultimap creates a new typed multimap backed by an empty HAMT.
+func MakeEmptyTypedMultimap[K abi.Keyer, V any, PV interface {
+	cbor.Er
+	*V
+}](s Store, outerBitwidth, innerBitwidth int, parseKey func(string) (K, error)) (*TypedMultimap[K, V, PV], error) {
+	mm, err := MakeEmptyMultimap(s, outerBitwidth, innerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedMultimap[K, V, PV]{mm: mm, parseKey: parseKey}, nil
+}
+
+// Root returns the root cid of the underlying HAMT.
+func (tm *TypedMultimap[K, V, PV]) Root() (cid.Cid, error) {
+	return tm.mm.Root()
+}
+
+// Add adds a value for a key.
+func (tm *TypedMultimap[K, V, PV]) Add(key K, value V) error {
+	return tm.mm.Add(key, PV(&value))
+}
+
+// AddMany appends `values` for a key, in order, storing the array of the key once.
+func (tm *TypedMultimap[K, V, PV]) AddMany(key K, values ...V) error {
+	if len(values) == 0 {
+		return nil
+	}
+	array, found, err := tm.mm.Get(key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if array, err = MakeEmptyArray(tm.mm.mp.store, tm.mm.innerBitwidth); err != nil {
+			return err
+		}
+	}
+	for i := range values {
+		if err := array.AppendContinuous(PV(&values[i])); err != nil {
+			return fmt.Errorf("failed to add multimap key %v value %v: %w", key.Key(), values[i], err)
+		}
+	}
+	return tm.putArray(key, array)
+}
+
+// Get returns the values of a key in the order they were inserted.
+func (tm *TypedMultimap[K, V, PV]) Get(key K) ([]V, error) {
+	var out []V
+	err := tm.ForEach(key, func(v V) error {
+		out = append(out, v)
+		return nil
+	})
+	return out, err
+}
+
+// ForEach iterates the values of a key in the order they were inserted.
+// Iteration halts if the function returns an error.
+func (tm *TypedMultimap[K, V, PV]) ForEach(key K, fn func(v V) error) error {
+	var raw cbg.Deferred
+	return tm.mm.ForEach(key, &raw, func(int64) error {
+		v, err := tm.decode(&raw)
+		if err != nil {
+			return err
+		}
+		return fn(v)
+	})
+}
+
+// ForAll iterates all values of all keys.
+// Iteration halts if the function returns an error.
+func (tm *TypedMultimap[K, V, PV]) ForAll(fn func(key K, v V) error) error {
+	var raw cbg.Deferred
+	return tm.mm.ForAll(func(k string, arr *Array) error {
+		key, err := tm.parseKey(k)
+		if err != nil {
+			return err
+		}
+		return arr.ForEach(&raw, func(int64) error {
+			v, err := tm.decode(&raw)
+			if err != nil {
+				return err
+			}
+			return fn(key, v)
+		})
+	})
+}
+
+// Len returns the number of keys, all HAMT nodes are loaded.
+func (tm *TypedMultimap[K, V, PV]) Len() (uint64, error) {
+	var n uint64
+	err := tm.mm.mp.ForEach(nil, func(string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// Count returns the number of values of a key.
+func (tm *TypedMultimap[K, V, PV]) Count(key K) (uint64, error) {
+	array, found, err := tm.mm.Get(key)
+	if err != nil || !found {
+		return 0, err
+	}
+	return array.Length(), nil
+}
+
+// Contains returns whether `value` is one of the values of a key, values are compared by their encoding.
+func (tm *TypedMultimap[K, V, PV]) Contains(key K, value V) (bool, error) {
+	target, err := encode(PV(&value))
+	if err != nil {
+		return false, err
+	}
+	var raw cbg.Deferred
+	err = tm.mm.ForEach(key, &raw, func(int64) error {
+		if bytes.Equal(raw.Raw, target) {
+			return errFound
+		}
+		return nil
+	})
+	if errors.Is(err, errFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// RemoveValue removes all occurrences of `value` from the values of a key, keeping the order of the others.
+// The key is removed once it has no value. Returns whether the value was present.
+func (tm *TypedMultimap[K, V, PV]) RemoveValue(key K, value V) (bool, error) {
+	target, err := encode(PV(&value))
+	if err != nil {
+		return false, err
+	}
+	array, found, err := tm.mm.Get(key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	// the values are kept continuous, so the remaining ones are copied into a new array
+	kept, err := MakeEmptyArray(tm.mm.mp.store, tm.mm.innerBitwidth)
+	if err != nil {
+		return false, err
+	}
+	removed := false
+	var raw cbg.Deferred
+	err = array.ForEach(&raw, func(int64) error {
+		if bytes.Equal(raw.Raw, target) {
+			removed = true
+			return nil
+		}
+		return kept.AppendContinuous(&cbg.Deferred{Raw: append([]byte(nil), raw.Raw...)})
+	})
+	if err != nil || !removed {
+		return false, err
+	}
+	if kept.Length() == 0 {
+		return true, tm.mm.RemoveAll(key)
+	}
+	return true, tm.putArray(key, kept)
+}
+
+// RemoveAll removes all values for a key.
+func (tm *TypedMultimap[K, V, PV]) RemoveAll(key K) error {
+	return tm.mm.RemoveAll(key)
+}
+
+func (tm *TypedMultimap[K, V, PV]) putArray(key K, array *Array) error {
+	c, err := array.Root()
+	if err != nil {
+		return fmt.Errorf("failed to flush child array: %w", err)
+	}
+	arrayRoot := cbg.CborCid(c)
+	if err := tm.mm.mp.Put(key, &arrayRoot); err != nil {
+		return fmt.Errorf("failed to store multimap values: %w", err)
+	}
+	return nil
+}
+
+func (tm *TypedMultimap[K, V, PV]) decode(raw *cbg.Deferred) (V, error) {
+	var v V
+	if err := PV(&v).UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		return v, fmt.Errorf("failed to decode multimap value: %w", err)
+	}
+	return v, nil
+}
+
+func encode(v cbor.Marshaler) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := v.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+//go:build simulate
+// +build simulate
+
+package adt
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedSet(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	set, err := MakeEmptySet(store, 5)
+	assert.Nil(t, err)
+	assert.Nil(t, set.Put(types.ActorKey(7)))
+	root, err := set.Root()
+	assert.Nil(t, err)
+
+	ts, err := AsTypedSet(store, root, 5, types.ParseActorKey)
+	assert.Nil(t, err)
+	assert.Nil(t, ts.AddMany(types.ActorKey(1), types.ActorKey(2), types.ActorKey(1)))
+	n, err := ts.Len()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), n)
+	found, err := ts.Contains(types.ActorKey(7))
+	assert.Nil(t, err)
+	assert.True(t, found)
+
+	assert.Nil(t, ts.Delete(types.ActorKey(7)))
+	keys, err := ts.CollectKeys()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []types.ActorKey{1, 2}, keys)
+}
+
+func TestTypedMultimap(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	store := AdtStore(ctx)
+
+	mm, err := MakeEmptyTypedMultimap[types.ActorKey, abi.TokenAmount](store, 5, 3, types.ParseActorKey)
+	assert.Nil(t, err)
+	assert.Nil(t, mm.AddMany(types.ActorKey(1), big.NewInt(1), big.NewInt(2), big.NewInt(1), big.NewInt(3)))
+	assert.Nil(t, mm.Add(types.ActorKey(2), big.NewInt(10)))
+	root, err := mm.Root()
+	assert.Nil(t, err)
+
+	// readable as untyped multimap
+	untyped, err := AsMultimap(store, root, 5, 3)
+	assert.Nil(t, err)
+	arr, found, err := untyped.Get(types.ActorKey(1))
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(4), arr.Length())
+
+	mm, err = AsTypedMultimap[types.ActorKey, abi.TokenAmount](store, root, 5, 3, types.ParseActorKey)
+	assert.Nil(t, err)
+	n, err := mm.Len()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), n)
+	count, err := mm.Count(types.ActorKey(1))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), count)
+	found, err = mm.Contains(types.ActorKey(1), big.NewInt(3))
+	assert.Nil(t, err)
+	assert.True(t, found)
+
+	removed, err := mm.RemoveValue(types.ActorKey(1), big.NewInt(1))
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	values, err := mm.Get(types.ActorKey(1))
+	assert.Nil(t, err)
+	assert.Equal(t, []abi.TokenAmount{big.NewInt(2), big.NewInt(3)}, values)
+	// appending after a removal keeps the values continuous
+	assert.Nil(t, mm.Add(types.ActorKey(1), big.NewInt(4)))
+	count, err = mm.Count(types.ActorKey(1))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	removed, err = mm.RemoveValue(types.ActorKey(2), big.NewInt(10))
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	removed, err = mm.RemoveValue(types.ActorKey(2), big.NewInt(10))
+	assert.Nil(t, err)
+	assert.False(t, removed)
+
+	total := big.Zero()
+	assert.Nil(t, mm.ForAll(func(key types.ActorKey, v abi.TokenAmount) error {
+		assert.Equal(t, types.ActorKey(1), key)
+		total = big.Add(total, v)
+		return nil
+	}))
+	assert.Equal(t, big.NewInt(9), total)
+}
@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/frc42dispatch"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+)
+
+// CallError the callee of Call exited with a non-OK exit code.
+// It unwraps to the exit code, so returning it from an actor method aborts with the callee's code.
+type CallError struct {
+	To         address.Address
+	Method     abi.MethodNum
+	Code       ferrors.ExitCode
+	ReturnData types.RawBytes
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("call to %s method %d failed with exit code %d", e.To, e.Method, e.Code)
+}
+
+// Unwrap returns the exit code of the callee.
+func (e *CallError) Unwrap() error {
+	return e.Code
+}
+
+// Call sends `params` to method `method` of actor `to` and decodes the return value as R, eg.
+// Call[*Params, Result](ctx, to, method, params, value). A nil `params` sends no params, and R is left
+// zero if the callee returns nothing. A non-OK exit code is returned as *CallError.
+func Call[P cbor.Marshaler, R any, PR interface {
+	cbor.Unmarshaler
+	*R
+}](ctx context.Context, to address.Address, method abi.MethodNum, params P, value abi.TokenAmount, opts ...SendOption) (R, error) {
+	var ret R
+	var raw types.RawBytes
+	if !IsNil(params) {
+		buf := bytes.NewBuffer(nil)
+		if err := params.MarshalCBOR(buf); err != nil {
+			return ret, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		raw = buf.Bytes()
+	}
+
+	receipt, err := Send(ctx, to, method, raw, value, opts...)
+	if err != nil {
+		return ret, err
+	}
+	if !receipt.ExitCode.IsSuccess() {
+		return ret, &CallError{To: to, Method: method, Code: receipt.ExitCode, ReturnData: receipt.ReturnData}
+	}
+	if len(receipt.ReturnData) > 0 {
+		if err := PR(&ret).UnmarshalCBOR(bytes.NewReader(receipt.ReturnData)); err != nil {
+			return ret, fmt.Errorf("failed to unmarshal return of method %d: %w", method, err)
+		}
+	}
+	return ret, nil
+}
+
+// CallByName is Call with the method number computed from the method name by FRC-42.
+func CallByName[P cbor.Marshaler, R any, PR interface {
+	cbor.Unmarshaler
+	*R
+}](ctx context.Context, to address.Address, name string, params P, value abi.TokenAmount, opts ...SendOption) (R, error) {
+	method, err := frc42dispatch.GenMethodNumber(name)
+	if err != nil {
+		var ret R
+		return ret, fmt.Errorf("invalid method name %s: %w", name, err)
+	}
+	return Call[P, R, PR](ctx, to, method, params, value, opts...)
+}
@@ -0,0 +1,93 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/frc42dispatch"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	to, err := address.NewIDAddress(1024)
+	assert.Nil(t, err)
+
+	params := types.CborString("ping")
+	rawParams := bytes.NewBuffer(nil)
+	assert.Nil(t, params.MarshalCBOR(rawParams))
+	ret := types.CborString("pong")
+	rawRet := bytes.NewBuffer(nil)
+	assert.Nil(t, ret.MarshalCBOR(rawRet))
+	retID, err := sys.Create(ctx, types.DAGCBOR, rawRet.Bytes())
+	assert.Nil(t, err)
+
+	sim.ExpectSend(simulated.SendMock{To: to, Method: 2, Params: rawParams.Bytes(), Value: big.NewInt(1),
+		Out: types.SendResult{ReturnID: retID, ReturnSize: uint32(rawRet.Len())}})
+	out, err := Call[*types.CborString, types.CborString](ctx, to, 2, &params, big.NewInt(1))
+	assert.Nil(t, err)
+	assert.Equal(t, ret, out)
+
+	// the method number is computed from the name
+	method, err := frc42dispatch.GenMethodNumber("Ping")
+	assert.Nil(t, err)
+	sim.ExpectSend(simulated.SendMock{To: to, Method: method, Params: rawParams.Bytes(), Value: big.Zero(),
+		Out: types.SendResult{ReturnID: retID, ReturnSize: uint32(rawRet.Len())}})
+	out, err = CallByName[*types.CborString, types.CborString](ctx, to, "Ping", &params, big.Zero())
+	assert.Nil(t, err)
+	assert.Equal(t, ret, out)
+	sent := sim.SentMessages()
+	assert.Equal(t, method, sent[len(sent)-1].Method)
+
+	_, err = CallByName[*types.CborString, types.CborString](ctx, to, "ping", &params, big.Zero())
+	assert.NotNil(t, err)
+
+	// no params and no return value
+	sim.ExpectSend(simulated.SendMock{To: to, Method: 3, Value: big.Zero()})
+	out, err = Call[*types.CborString, types.CborString](ctx, to, 3, nil, big.Zero())
+	assert.Nil(t, err)
+	assert.Equal(t, types.CborString(""), out)
+}
+
+func TestCallError(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	to, err := address.NewIDAddress(1024)
+	assert.Nil(t, err)
+
+	errData := []byte{0x63, 'b', 'a', 'd'}
+	errID, err := sys.Create(ctx, types.DAGCBOR, errData)
+	assert.Nil(t, err)
+	sim.ExpectSend(simulated.SendMock{To: to, Method: 2, Value: big.Zero(),
+		Out: types.SendResult{ExitCode: ferrors.USR_INSUFFICIENT_FUNDS, ReturnID: errID, ReturnSize: uint32(len(errData))}})
+	_, err = Call[*types.CborString, types.CborString](ctx, to, 2, nil, big.Zero())
+
+	var callErr *CallError
+	assert.True(t, errors.As(err, &callErr))
+	assert.Equal(t, to, callErr.To)
+	assert.Equal(t, ferrors.USR_INSUFFICIENT_FUNDS, callErr.Code)
+	assert.Equal(t, types.RawBytes(errData), callErr.ReturnData)
+	assert.True(t, errors.Is(err, ferrors.USR_INSUFFICIENT_FUNDS))
+	assert.False(t, errors.Is(err, ferrors.USR_FORBIDDEN))
+}
+
+func TestSendGasUsed(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	to, err := address.NewIDAddress(1024)
+	assert.Nil(t, err)
+
+	// the gas used is reported without any option
+	sim.ExpectSend(simulated.SendMock{To: to, Method: 2, Value: big.Zero(), Out: types.SendResult{GasUsed: 10}})
+	ret, err := Send(ctx, to, 2, nil, big.Zero())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), ret.GasUsed)
+}
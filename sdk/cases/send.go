@@ -38,7 +38,7 @@ func Invoke(_ uint32) uint32 { //nolint
 		ret, err := sdk.Send(ctx, addr, 0, []byte{}, abi.NewTokenAmount(1))
 		assert.Nil(t, err, "send %v", err)
 		assert.Equal(t, 0, int(ret.ExitCode))
-		assert.Equal(t, 0, int(ret.GasUsed))
+		assert.True(t, ret.GasUsed > 0)
 		assert.Equal(t, "", string(ret.ReturnData))
 	case 3:
 		//sender does not have funds to transfer (balance 10, transfer 5000000000000000) (5: insufficient funds)
@@ -24,10 +24,11 @@ func Invoke(_ uint32) uint32 { //nolint
 	preBalance, err := sdk.BalanceOf(ctx, abi.ActorID(actorId))
 	assert.Nil(t, err)
 
-	ret, err := sdk.Send(ctx, addr, 0, []byte{}, abi.NewTokenAmount(1), sdk.WithReadonly())
+	// value can't be transferred in readonly mode
+	ret, err := sdk.Send(ctx, addr, 0, []byte{}, abi.NewTokenAmount(0), sdk.WithReadonly(), sdk.WithGasLimit(10_000_000))
 	assert.Nil(t, err, "send %v", err)
 	assert.Equal(t, 0, int(ret.ExitCode))
-	assert.Equal(t, 0, int(ret.GasUsed))
+	assert.True(t, ret.GasUsed > 0)
 	assert.Equal(t, "", string(ret.ReturnData))
 
 	balance, err := sdk.BalanceOf(ctx, abi.ActorID(actorId))
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -23,9 +24,91 @@ func VerifySignature(
 	return sys.VerifySignature(ctx, signature, signer, plainText)
 }
 
+// Hash hashes input data using the hash function `code`, eg. types.HashKeccak256, and returns the digest.
+func Hash(ctx context.Context, code types.HashCode, data []byte) ([]byte, error) {
+	return sys.Hash(ctx, code, data)
+}
+
 // HashBlake2b hashes input data using blake2b with 256 bit output.
 func HashBlake2b(ctx context.Context, data []byte) ([32]byte, error) {
-	return sys.HashBlake2b(ctx, data)
+	var digest [32]byte
+	err := hashInto(ctx, types.HashBlake2b256, data, digest[:])
+	return digest, err
+}
+
+// HashBlake2b512 hashes input data using blake2b with 512 bit output.
+func HashBlake2b512(ctx context.Context, data []byte) ([64]byte, error) {
+	var digest [64]byte
+	err := hashInto(ctx, types.HashBlake2b512, data, digest[:])
+	return digest, err
+}
+
+// HashSha256 hashes input data using sha2-256.
+func HashSha256(ctx context.Context, data []byte) ([32]byte, error) {
+	var digest [32]byte
+	err := hashInto(ctx, types.HashSha256, data, digest[:])
+	return digest, err
+}
+
+// HashKeccak256 hashes input data using keccak-256, the hash of the EVM, not the standardized sha3-256.
+func HashKeccak256(ctx context.Context, data []byte) ([32]byte, error) {
+	var digest [32]byte
+	err := hashInto(ctx, types.HashKeccak256, data, digest[:])
+	return digest, err
+}
+
+// HashRipemd160 hashes input data using ripemd-160.
+func HashRipemd160(ctx context.Context, data []byte) ([20]byte, error) {
+	var digest [20]byte
+	err := hashInto(ctx, types.HashRipemd160, data, digest[:])
+	return digest, err
+}
+
+// RecoverSecp256k1PublicKey recovers the uncompressed secp256k1 public key which signed the message hash `hash`
+// with the signature `sig`, laid out as r || s || recovery id like Filecoin signatures. The recovery id must be 0
+// or 1: the v of an Ethereum signature, 27 or 28, must be normalized by subtracting 27 first.
+func RecoverSecp256k1PublicKey(ctx context.Context, hash [32]byte, sig [65]byte) ([65]byte, error) {
+	return sys.RecoverSecp256k1PublicKey(ctx, hash, sig)
+}
+
+// Secp256k1Address returns the f1 address of the uncompressed secp256k1 public key `pubkey`.
+func Secp256k1Address(pubkey [65]byte) (address.Address, error) {
+	return address.NewSecp256k1Address(pubkey[:])
+}
+
+// EthAddressFromPublicKey returns the Ethereum address of the uncompressed secp256k1 public key `pubkey`, the
+// last 20 bytes of the keccak-256 hash of the key without its 0x04 prefix.
+func EthAddressFromPublicKey(ctx context.Context, pubkey [65]byte) ([20]byte, error) {
+	var ethAddr [20]byte
+	hash, err := HashKeccak256(ctx, pubkey[1:])
+	if err != nil {
+		return ethAddr, err
+	}
+	copy(ethAddr[:], hash[12:])
+	return ethAddr, nil
+}
+
+// DelegatedAddressFromPublicKey returns the f4 address of the Ethereum account of the uncompressed secp256k1
+// public key `pubkey`.
+func DelegatedAddressFromPublicKey(ctx context.Context, pubkey [65]byte) (address.Address, error) {
+	ethAddr, err := EthAddressFromPublicKey(ctx, pubkey)
+	if err != nil {
+		return address.Undef, err
+	}
+	return address.NewDelegatedAddress(types.EthereumAddressManagerActorID, ethAddr[:])
+}
+
+// hashInto hashes input data using the hash function `code` into `digest`, which has the digest length.
+func hashInto(ctx context.Context, code types.HashCode, data []byte, digest []byte) error {
+	result, err := sys.Hash(ctx, code, data)
+	if err != nil {
+		return err
+	}
+	if len(result) != len(digest) {
+		return fmt.Errorf("unexpected digest length %d of hash %#x", len(result), uint64(code))
+	}
+	copy(digest, result)
+	return nil
 }
 
 // ComputeUnsealedSectorCid computes an unsealed sector CID (CommD) from its constituent piece CIDs (CommPs) and sizes.
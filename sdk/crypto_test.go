@@ -0,0 +1,77 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	gocrypto "github.com/filecoin-project/go-crypto"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+
+	sha, err := HashSha256(ctx, []byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", hex.EncodeToString(sha[:]))
+
+	keccak, err := HashKeccak256(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(keccak[:]))
+
+	ripemd, err := HashRipemd160(ctx, []byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc", hex.EncodeToString(ripemd[:]))
+
+	blake, err := HashBlake2b(ctx, []byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319", hex.EncodeToString(blake[:]))
+	sysBlake, err := sys.HashBlake2b(ctx, []byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, blake, sysBlake)
+
+	blake512, err := HashBlake2b512(ctx, []byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923", hex.EncodeToString(blake512[:]))
+
+	_, err = Hash(ctx, types.HashCode(0x13), []byte("abc"))
+	assert.NotNil(t, err)
+}
+
+func TestRecoverSecp256k1PublicKey(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+
+	priv := make([]byte, 32)
+	priv[31] = 1
+	hash, err := HashKeccak256(ctx, []byte("hello"))
+	assert.Nil(t, err)
+	sig, err := gocrypto.Sign(priv, hash[:])
+	assert.Nil(t, err)
+
+	var sigArr [65]byte
+	copy(sigArr[:], sig)
+	pubkey, err := RecoverSecp256k1PublicKey(ctx, hash, sigArr)
+	assert.Nil(t, err)
+	assert.Equal(t, gocrypto.PublicKey(priv), pubkey[:])
+
+	f1, err := Secp256k1Address(pubkey)
+	assert.Nil(t, err)
+	expectF1, err := address.NewSecp256k1Address(gocrypto.PublicKey(priv))
+	assert.Nil(t, err)
+	assert.Equal(t, expectF1, f1)
+
+	ethAddr, err := EthAddressFromPublicKey(ctx, pubkey)
+	assert.Nil(t, err)
+	assert.Equal(t, "7e5f4552091a69125d5dfcb7b8c2659029395bdf", hex.EncodeToString(ethAddr[:]))
+
+	f4, err := DelegatedAddressFromPublicKey(ctx, pubkey)
+	assert.Nil(t, err)
+	assert.Equal(t, address.Delegated, f4.Protocol())
+}
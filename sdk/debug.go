@@ -2,9 +2,12 @@ package sdk
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
 
 // Logger is a debug-only logger that uses the FVM syscalls.
@@ -53,3 +56,151 @@ func (l *logger) StoreArtifact(ctx context.Context, name string, data []byte) {
 		_ = sys.StoreArtifact(ctx, name, data) //todo check error and abort?
 	}
 }
+
+// Level the severity of a leveled log entry.
+type Level = types.LogLevel
+
+const (
+	LevelTrace = types.LogTrace
+	LevelDebug = types.LogDebug
+	LevelInfo  = types.LogInfo
+	LevelWarn  = types.LogWarn
+	LevelError = types.LogError
+)
+
+// LeveledLogger a debug-only logger writing leveled entries with key-value fields, eg.
+// log.Info(ctx, "transfer", "from", from, "amount", amount). Fields, including those added by With, are only
+// formatted if debugging is enabled and the level of the entry is not below the level of the logger.
+//
+// Whether debugging is enabled is checked by the first entry and cached in the logger instance, shared with the
+// loggers derived from it by With. A package level logger is created again by each invocation of a wasm actor,
+// but lives across invocations in the simulator, use a logger per test there.
+type LeveledLogger struct {
+	level  Level
+	kv     []interface{}
+	enable *enableFlag
+}
+
+type enableFlag struct {
+	checked bool
+	enabled bool
+}
+
+// NewLeveledLogger creates a logger writing the entries of level `level` and above.
+func NewLeveledLogger(level Level) *LeveledLogger {
+	return &LeveledLogger{level: level, enable: &enableFlag{}}
+}
+
+// With returns a logger adding the key-value pairs `kv` to each entry, it shares the enabled check with `l`.
+// The pairs are kept as is and formatted by each entry written.
+func (l *LeveledLogger) With(kv ...interface{}) *LeveledLogger {
+	withKV := make([]interface{}, len(l.kv), len(l.kv)+len(kv)+1)
+	copy(withKV, l.kv)
+	withKV = append(withKV, kv...)
+	if len(kv)%2 == 1 {
+		// keep the pairs of the next call aligned
+		withKV = append(withKV, "!MISSING")
+	}
+	return &LeveledLogger{level: l.level, kv: withKV, enable: l.enable}
+}
+
+// Enabled whether an entry of level `level` is written.
+func (l *LeveledLogger) Enabled(ctx context.Context, level Level) bool {
+	if level < l.level {
+		return false
+	}
+	if !l.enable.checked {
+		l.enable.enabled, _ = sys.Enabled(ctx)
+		l.enable.checked = true
+	}
+	return l.enable.enabled
+}
+
+// Trace writes an entry of level LevelTrace.
+func (l *LeveledLogger) Trace(ctx context.Context, msg string, kv ...interface{}) {
+	l.log(ctx, LevelTrace, msg, kv)
+}
+
+// Debug writes an entry of level LevelDebug.
+func (l *LeveledLogger) Debug(ctx context.Context, msg string, kv ...interface{}) {
+	l.log(ctx, LevelDebug, msg, kv)
+}
+
+// Info writes an entry of level LevelInfo.
+func (l *LeveledLogger) Info(ctx context.Context, msg string, kv ...interface{}) {
+	l.log(ctx, LevelInfo, msg, kv)
+}
+
+// Warn writes an entry of level LevelWarn.
+func (l *LeveledLogger) Warn(ctx context.Context, msg string, kv ...interface{}) {
+	l.log(ctx, LevelWarn, msg, kv)
+}
+
+// Error writes an entry of level LevelError.
+func (l *LeveledLogger) Error(ctx context.Context, msg string, kv ...interface{}) {
+	l.log(ctx, LevelError, msg, kv)
+}
+
+func (l *LeveledLogger) log(ctx context.Context, level Level, msg string, kv []interface{}) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	fields := make([]types.LogField, 0, (len(l.kv)+len(kv)+1)/2)
+	fields = appendLogFields(appendLogFields(fields, l.kv), kv)
+	_ = sys.LogEntry(ctx, types.LogEntry{Level: level, Msg: msg, Fields: fields}) //todo check error and abort?
+}
+
+// appendLogFields appends the key-value pairs `kv` to `fields`, a key without value gets the value "!MISSING".
+func appendLogFields(fields []types.LogField, kv []interface{}) []types.LogField {
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = formatLogValue(kv[i])
+		}
+		value := "!MISSING"
+		if i+1 < len(kv) {
+			value = formatLogValue(kv[i+1])
+		}
+		fields = append(fields, types.LogField{Key: key, Value: value})
+	}
+	return fields
+}
+
+// formatLogValue formats common values without reflection, other values are formatted by fmt.
+func formatLogValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case []byte:
+		return hex.EncodeToString(v)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,62 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingStringer struct {
+	calls int
+}
+
+func (c *countingStringer) String() string {
+	c.calls++
+	return "counted"
+}
+
+func TestLeveledLogger(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	log := NewLeveledLogger(LevelInfo).With("actor", abi.ActorID(1000))
+
+	log.Debug(ctx, "skipped", "key", "value")
+	log.Info(ctx, "transfer", "amount", 10, "memo", "a b", "ok", true, "data", []byte{0xab})
+	log.Error(ctx, "failed", errors.New("boom"))
+
+	entries := sim.LogEntries()
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, types.LogEntry{Level: LevelInfo, Msg: "transfer", Fields: []types.LogField{
+		{Key: "actor", Value: "1000"},
+		{Key: "amount", Value: "10"},
+		{Key: "memo", Value: "a b"},
+		{Key: "ok", Value: "true"},
+		{Key: "data", Value: "ab"},
+	}}, entries[0])
+	assert.Equal(t, `[INFO] transfer actor=1000 amount=10 memo="a b" ok=true data=ab`, entries[0].String())
+
+	value, ok := entries[1].Field("boom")
+	assert.True(t, ok)
+	assert.Equal(t, "!MISSING", value)
+
+	// fields added by With are formatted by the entries written only
+	counter := &countingStringer{}
+	withCounter := log.With("counter", counter, "odd")
+	withCounter.Debug(ctx, "skipped")
+	assert.Equal(t, 0, counter.calls)
+	withCounter.Info(ctx, "counted", "key", "value")
+	assert.Equal(t, 1, counter.calls)
+	entries = sim.LogEntries()
+	assert.Equal(t, types.LogEntry{Level: LevelInfo, Msg: "counted", Fields: []types.LogField{
+		{Key: "actor", Value: "1000"},
+		{Key: "counter", Value: "counted"},
+		{Key: "odd", Value: "!MISSING"},
+		{Key: "key", Value: "value"},
+	}}, entries[len(entries)-1])
+}
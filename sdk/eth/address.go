@@ -0,0 +1,167 @@
+// Package eth converts between Ethereum addresses and Filecoin addresses, so actors can accept Ethereum-native
+// addresses from users.
+package eth
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+)
+
+// AddressLength the length of an Ethereum address in bytes.
+const AddressLength = 20
+
+// maskedIDPrefix the first 12 bytes of a masked ID address, the last 8 bytes are the big endian actor id.
+var maskedIDPrefix = [12]byte{0xff}
+
+// ErrInvalidAddress the address can't be converted.
+var ErrInvalidAddress = errors.New("invalid ethereum address")
+
+// Address a 20 byte Ethereum address, either the f410 sub-address of an actor or a masked ID address
+// 0xff0000000000000000000000<actor id> standing for an actor without f410 address.
+type Address [AddressLength]byte
+
+// MaskedIDAddress returns the masked ID address of the actor `actorID`.
+func MaskedIDAddress(actorID abi.ActorID) Address {
+	var addr Address
+	copy(addr[:], maskedIDPrefix[:])
+	binary.BigEndian.PutUint64(addr[12:], uint64(actorID))
+	return addr
+}
+
+// ParseAddress parses a hex Ethereum address with or without 0x prefix, a mixed case address must have a valid
+// EIP-55 checksum, computed by the keccak-256 hash syscall.
+func ParseAddress(ctx context.Context, s string) (Address, error) {
+	var addr Address
+	hexStr := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(hexStr) != AddressLength*2 {
+		return addr, fmt.Errorf("%w: %s has length %d", ErrInvalidAddress, s, len(hexStr))
+	}
+	if _, err := hex.Decode(addr[:], []byte(hexStr)); err != nil {
+		return addr, fmt.Errorf("%w: %s: %v", ErrInvalidAddress, s, err)
+	}
+	if hexStr == strings.ToLower(hexStr) || hexStr == strings.ToUpper(hexStr) {
+		return addr, nil
+	}
+	checksum, err := addr.ChecksumString(ctx)
+	if err != nil {
+		return addr, err
+	}
+	if checksum[2:] != hexStr {
+		return addr, fmt.Errorf("%w: %s has a bad checksum", ErrInvalidAddress, s)
+	}
+	return addr, nil
+}
+
+// AddressFromFilecoin converts an f410 address into its Ethereum address and an ID address into its masked ID
+// address, other addresses have no Ethereum address.
+func AddressFromFilecoin(addr address.Address) (Address, error) {
+	var ethAddr Address
+	switch addr.Protocol() {
+	case address.ID:
+		id, err := address.IDFromAddress(addr)
+		if err != nil {
+			return ethAddr, err
+		}
+		return MaskedIDAddress(abi.ActorID(id)), nil
+	case address.Delegated:
+		payload := addr.Payload()
+		namespace, n := binary.Uvarint(payload)
+		if n <= 0 || namespace != types.EthereumAddressManagerActorID || len(payload)-n != AddressLength {
+			return ethAddr, fmt.Errorf("%w: %s isn't an f410 address", ErrInvalidAddress, addr)
+		}
+		copy(ethAddr[:], payload[n:])
+		return ethAddr, nil
+	}
+	return ethAddr, fmt.Errorf("%w: %s has no ethereum address", ErrInvalidAddress, addr)
+}
+
+// AddressFromActor returns the Ethereum address of the actor `actorID`: its f410 address if it has one, its masked
+// ID address if it has no delegated address or one outside the namespace of the Ethereum address manager. Errors
+// looking up the delegated address, eg. of a missing actor, are returned.
+func AddressFromActor(ctx context.Context, actorID abi.ActorID) (Address, error) {
+	delegated, err := sdk.LookupDelegatedAddress(ctx, actorID)
+	if err != nil {
+		return Address{}, err
+	}
+	if delegated.Protocol() != address.Delegated {
+		return MaskedIDAddress(actorID), nil
+	}
+	if namespace, n := binary.Uvarint(delegated.Payload()); n > 0 && namespace != types.EthereumAddressManagerActorID {
+		return MaskedIDAddress(actorID), nil
+	}
+	return AddressFromFilecoin(delegated)
+}
+
+// IsMaskedID returns the actor id of a masked ID address, false if `a` isn't a masked ID address.
+func (a Address) IsMaskedID() (abi.ActorID, bool) {
+	if [12]byte(a[:12]) != maskedIDPrefix {
+		return 0, false
+	}
+	return abi.ActorID(binary.BigEndian.Uint64(a[12:])), true
+}
+
+// FilecoinAddress converts the address into the ID address of a masked ID address, the f410 address otherwise.
+func (a Address) FilecoinAddress() (address.Address, error) {
+	if id, ok := a.IsMaskedID(); ok {
+		return address.NewIDAddress(uint64(id))
+	}
+	return address.NewDelegatedAddress(types.EthereumAddressManagerActorID, a[:])
+}
+
+// String formats the address as 0x prefixed lowercase hex, see ChecksumString for the EIP-55 form.
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// ChecksumString formats the address as 0x prefixed hex with the EIP-55 checksum, computed by the keccak-256 hash
+// syscall.
+func (a Address) ChecksumString(ctx context.Context) (string, error) {
+	lower := hex.EncodeToString(a[:])
+	hash, err := sdk.HashKeccak256(ctx, []byte(lower))
+	if err != nil {
+		return "", err
+	}
+
+	out := []byte("0x" + lower)
+	for i := 0; i < len(lower); i++ {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if lower[i] >= 'a' && nibble >= 8 {
+			out[i+2] = lower[i] - 'a' + 'A'
+		}
+	}
+	return string(out), nil
+}
+
+// IsEthAccount checks whether `addr` is an Ethereum account, an EthAccount actor.
+func IsEthAccount(ctx context.Context, addr address.Address) (bool, error) {
+	return isActorType(ctx, addr, types.EthAccount)
+}
+
+// IsEvmActor checks whether `addr` is an EVM smart contract actor.
+func IsEvmActor(ctx context.Context, addr address.Address) (bool, error) {
+	return isActorType(ctx, addr, types.Evm)
+}
+
+func isActorType(ctx context.Context, addr address.Address, actorT types.ActorType) (bool, error) {
+	codeCid, err := sdk.GetActorCodeCid(ctx, addr)
+	if err != nil {
+		return false, err
+	}
+	actorType, err := sdk.GetBuiltinActorType(ctx, codeCid)
+	if err != nil {
+		return false, err
+	}
+	return actorType == actorT, nil
+}
@@ -0,0 +1,111 @@
+//go:build simulate
+// +build simulate
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/builtin"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	for _, s := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		addr, err := ParseAddress(ctx, s)
+		assert.Nil(t, err)
+		checksum, err := addr.ChecksumString(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, s, checksum)
+	}
+
+	_, err := ParseAddress(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+	addr, err := ParseAddress(ctx, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	assert.Nil(t, err)
+	assert.Equal(t, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", addr.String())
+	_, err = ParseAddress(ctx, "0x5aaeb6")
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+}
+
+func TestFilecoinAddress(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	ethAddr, err := ParseAddress(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	assert.Nil(t, err)
+	f4, err := ethAddr.FilecoinAddress()
+	assert.Nil(t, err)
+	assert.Equal(t, address.Delegated, f4.Protocol())
+	back, err := AddressFromFilecoin(f4)
+	assert.Nil(t, err)
+	assert.Equal(t, ethAddr, back)
+	_, ok := ethAddr.IsMaskedID()
+	assert.False(t, ok)
+
+	masked := MaskedIDAddress(1024)
+	assert.Equal(t, "0xff00000000000000000000000000000000000400", masked.String())
+	id, ok := masked.IsMaskedID()
+	assert.True(t, ok)
+	assert.Equal(t, abi.ActorID(1024), id)
+	idAddr, err := masked.FilecoinAddress()
+	assert.Nil(t, err)
+	assert.Equal(t, address.ID, idAddr.Protocol())
+	back, err = AddressFromFilecoin(idAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, masked, back)
+
+	f1, err := address.NewSecp256k1Address(make([]byte, 65))
+	assert.Nil(t, err)
+	_, err = AddressFromFilecoin(f1)
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+}
+
+func TestActor(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	ethAddr, err := ParseAddress(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	assert.Nil(t, err)
+	f4, err := ethAddr.FilecoinAddress()
+	assert.Nil(t, err)
+	f1, err := address.NewSecp256k1Address(make([]byte, 65))
+	assert.Nil(t, err)
+	otherF4, err := address.NewDelegatedAddress(1000, []byte("bob"))
+	assert.Nil(t, err)
+	sim.SetActor(100, f4, builtin.Actor{Code: simulated.EthAccountCid})
+	sim.SetActor(101, f1, builtin.Actor{Code: simulated.AccountCid})
+	sim.SetActor(102, otherF4, builtin.Actor{Code: simulated.EvmCid})
+
+	addr, err := AddressFromActor(ctx, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, ethAddr, addr)
+	addr, err = AddressFromActor(ctx, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, MaskedIDAddress(101), addr)
+	addr, err = AddressFromActor(ctx, 102)
+	assert.Nil(t, err)
+	assert.Equal(t, MaskedIDAddress(102), addr)
+	_, err = AddressFromActor(ctx, 103)
+	assert.True(t, errors.Is(err, ferrors.NotFound))
+
+	isEthAccount, err := IsEthAccount(ctx, f4)
+	assert.Nil(t, err)
+	assert.True(t, isEthAccount)
+	isEthAccount, err = IsEthAccount(ctx, f1)
+	assert.Nil(t, err)
+	assert.False(t, isEthAccount)
+	isEvm, err := IsEvmActor(ctx, otherF4)
+	assert.Nil(t, err)
+	assert.True(t, isEvm)
+	isEvm, err = IsEvmActor(ctx, f4)
+	assert.Nil(t, err)
+	assert.False(t, isEvm)
+}
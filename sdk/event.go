@@ -1,13 +1,211 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 
+	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	cbg "github.com/whyrusleeping/cbor-gen"
 )
 
+// EventTypeKey the key of the first entry of a typed event, its value is the event name.
+const EventTypeKey = "$type"
+
+// ErrEventMismatch the event decoded by DecodeEvent is of another type.
+var ErrEventMismatch = errors.New("event type mismatch")
+
+// EventNamer a typed event naming itself, gen names a typed event after its struct type otherwise.
+type EventNamer interface {
+	EventName() string
+}
+
+// EventMarshaler a typed event building its entries, implemented by the event code generated by gen.
+type EventMarshaler interface {
+	MarshalEvent() (*types.ActorEvent, error)
+}
+
+// EventUnmarshaler a typed event decoding its entries, implemented by the event code generated by gen.
+type EventUnmarshaler interface {
+	UnmarshalEvent(evt types.ActorEvent) error
+}
+
 // EmitEvent emit event to fvm
 func EmitEvent(ctx context.Context, evt types.ActorEvent) error {
 	return sys.EmitEvent(ctx, evt)
 }
+
+// Emit emits a typed event, eg. a struct Transfer{From, To address.Address; Amount abi.TokenAmount} listed by the
+// Events method of the state, whose MarshalEvent is generated by gen.
+func Emit(ctx context.Context, evt EventMarshaler) error {
+	actorEvt, err := evt.MarshalEvent()
+	if err != nil {
+		return err
+	}
+	return EmitEvent(ctx, *actorEvt)
+}
+
+// DecodeEvent decodes an event emitted by Emit into a typed event E, returns ErrEventMismatch if the event is of
+// another type.
+func DecodeEvent[E any, PE interface {
+	*E
+	EventUnmarshaler
+}](evt types.ActorEvent) (E, error) {
+	var out E
+	err := PE(&out).UnmarshalEvent(evt)
+	return out, err
+}
+
+// EventWriter builds the entries of a typed event for the code generated by gen. The first entry, keyed
+// EventTypeKey, holds the event name and is indexed, then each field becomes an entry holding its cbor encoding
+// with codec IPLDRAW, as required by FIP-0049. The first error is kept and returned by Event.
+type EventWriter struct {
+	entries []*types.Entry
+	err     error
+}
+
+// NewEventWriter creates a writer for an event named `name`.
+func NewEventWriter(name string) *EventWriter {
+	w := &EventWriter{}
+	w.String(EventTypeKey, types.FLAGINDEXEDALL, name)
+	return w
+}
+
+// Value adds an entry holding a cbor marshaler, a nil pointer is written as cbor null.
+func (w *EventWriter) Value(key string, flags types.Flags, v cbor.Marshaler) {
+	w.write(key, flags, func(buf io.Writer) error {
+		if IsNil(v) {
+			_, err := buf.Write(cbg.CborNull)
+			return err
+		}
+		return v.MarshalCBOR(buf)
+	})
+}
+
+// Bool adds an entry holding a bool.
+func (w *EventWriter) Bool(key string, flags types.Flags, v bool) {
+	w.write(key, flags, func(buf io.Writer) error {
+		return cbg.WriteBool(buf, v)
+	})
+}
+
+// Int adds an entry holding a signed integer.
+func (w *EventWriter) Int(key string, flags types.Flags, v int64) {
+	w.write(key, flags, cbg.CborInt(v).MarshalCBOR)
+}
+
+// Uint adds an entry holding an unsigned integer.
+func (w *EventWriter) Uint(key string, flags types.Flags, v uint64) {
+	w.write(key, flags, func(buf io.Writer) error {
+		return cbg.WriteMajorTypeHeader(buf, cbg.MajUnsignedInt, v)
+	})
+}
+
+// String adds an entry holding a text string.
+func (w *EventWriter) String(key string, flags types.Flags, v string) {
+	w.write(key, flags, func(buf io.Writer) error {
+		if err := cbg.WriteMajorTypeHeader(buf, cbg.MajTextString, uint64(len(v))); err != nil {
+			return err
+		}
+		_, err := io.WriteString(buf, v)
+		return err
+	})
+}
+
+// Bytes adds an entry holding a byte string.
+func (w *EventWriter) Bytes(key string, flags types.Flags, v []byte) {
+	w.write(key, flags, func(buf io.Writer) error {
+		if err := cbg.WriteMajorTypeHeader(buf, cbg.MajByteString, uint64(len(v))); err != nil {
+			return err
+		}
+		_, err := buf.Write(v)
+		return err
+	})
+}
+
+// Event returns the event built so far, or the first error met while encoding its entries.
+func (w *EventWriter) Event() (*types.ActorEvent, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return &types.ActorEvent{Entries: w.entries}, nil
+}
+
+func (w *EventWriter) write(key string, flags types.Flags, encode func(io.Writer) error) {
+	if w.err != nil {
+		return
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := encode(buf); err != nil {
+		w.err = fmt.Errorf("failed to encode event field %s: %w", key, err)
+		return
+	}
+	w.entries = append(w.entries, &types.Entry{Flags: flags, Key: key, Codec: types.IPLDRAW, Value: buf.Bytes()})
+}
+
+// ReadEvent checks that `evt` is an event named `name` built by EventWriter, then calls fn with the key and value
+// of each of its other entries. Returns ErrEventMismatch if the event is of another type.
+func ReadEvent(evt types.ActorEvent, name string, fn func(key string, value []byte) error) error {
+	if len(evt.Entries) == 0 || evt.Entries[0].Key != EventTypeKey {
+		return ErrEventMismatch
+	}
+	if got, err := ReadEventString(evt.Entries[0].Value); err != nil || got != name {
+		return ErrEventMismatch
+	}
+	for _, entry := range evt.Entries[1:] {
+		if err := fn(entry.Key, entry.Value); err != nil {
+			return fmt.Errorf("failed to decode event field %s: %w", entry.Key, err)
+		}
+	}
+	return nil
+}
+
+// IsEventNull reports whether an entry value is cbor null, as written for a nil pointer.
+func IsEventNull(value []byte) bool {
+	return bytes.Equal(value, cbg.CborNull)
+}
+
+// ReadEventValue decodes an entry value into a cbor unmarshaler.
+func ReadEventValue(value []byte, v cbor.Unmarshaler) error {
+	return v.UnmarshalCBOR(bytes.NewReader(value))
+}
+
+// ReadEventBool decodes an entry value written by EventWriter.Bool.
+func ReadEventBool(value []byte) (bool, error) {
+	var b cbg.CborBool
+	err := b.UnmarshalCBOR(bytes.NewReader(value))
+	return bool(b), err
+}
+
+// ReadEventInt decodes an entry value written by EventWriter.Int.
+func ReadEventInt(value []byte) (int64, error) {
+	var i cbg.CborInt
+	err := i.UnmarshalCBOR(bytes.NewReader(value))
+	return int64(i), err
+}
+
+// ReadEventUint decodes an entry value written by EventWriter.Uint.
+func ReadEventUint(value []byte) (uint64, error) {
+	maj, extra, err := cbg.CborReadHeader(bytes.NewReader(value))
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajUnsignedInt {
+		return 0, fmt.Errorf("wrong type for uint field")
+	}
+	return extra, nil
+}
+
+// ReadEventString decodes an entry value written by EventWriter.String.
+func ReadEventString(value []byte) (string, error) {
+	return cbg.ReadString(bytes.NewReader(value))
+}
+
+// ReadEventBytes decodes an entry value written by EventWriter.Bytes.
+func ReadEventBytes(value []byte) ([]byte, error) {
+	return cbg.ReadByteArray(bytes.NewReader(value), cbg.ByteArrayMaxLen)
+}
@@ -0,0 +1,139 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// transferEvent with the event code gen writes for its tags:
+//
+//	From   address.Address `event:"from,indexed"`
+//	To     *address.Address `event:"to,indexed_value"`
+//	Amount abi.TokenAmount `event:"amount"`
+type transferEvent struct {
+	From   address.Address
+	To     *address.Address
+	Amount abi.TokenAmount
+	Memo   string
+	Nonce  uint64
+	Delta  int64
+	Ok     bool
+	Data   []byte
+}
+
+func (t *transferEvent) MarshalEvent() (*types.ActorEvent, error) {
+	w := NewEventWriter("transfer")
+	w.Value("from", types.FLAGINDEXEDALL, &t.From)
+	w.Value("to", types.FLAGINDEXEDVALUE, t.To)
+	w.Value("amount", 0, &t.Amount)
+	w.String("Memo", 0, string(t.Memo))
+	w.Uint("Nonce", 0, uint64(t.Nonce))
+	w.Int("Delta", 0, int64(t.Delta))
+	w.Bool("Ok", 0, bool(t.Ok))
+	w.Bytes("Data", 0, []byte(t.Data))
+	return w.Event()
+}
+
+func (t *transferEvent) UnmarshalEvent(evt types.ActorEvent) error {
+	return ReadEvent(evt, "transfer", func(key string, value []byte) error {
+		switch key {
+		case "from":
+			return ReadEventValue(value, &t.From)
+		case "to":
+			if IsEventNull(value) {
+				t.To = nil
+				return nil
+			}
+			t.To = new(address.Address)
+			return ReadEventValue(value, t.To)
+		case "amount":
+			return ReadEventValue(value, &t.Amount)
+		case "Memo":
+			v, err := ReadEventString(value)
+			t.Memo = string(v)
+			return err
+		case "Nonce":
+			v, err := ReadEventUint(value)
+			t.Nonce = uint64(v)
+			return err
+		case "Delta":
+			v, err := ReadEventInt(value)
+			t.Delta = int64(v)
+			return err
+		case "Ok":
+			v, err := ReadEventBool(value)
+			t.Ok = bool(v)
+			return err
+		case "Data":
+			v, err := ReadEventBytes(value)
+			t.Data = []byte(v)
+			return err
+		}
+		return nil
+	})
+}
+
+type approvalEvent struct {
+	Owner address.Address
+}
+
+func (t *approvalEvent) UnmarshalEvent(evt types.ActorEvent) error {
+	return ReadEvent(evt, "approvalEvent", func(key string, value []byte) error {
+		if key == "Owner" {
+			return ReadEventValue(value, &t.Owner)
+		}
+		return nil
+	})
+}
+
+func TestEmit(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	from, _ := address.NewIDAddress(1)
+	to, _ := address.NewIDAddress(2)
+	evt := transferEvent{From: from, To: &to, Amount: abi.NewTokenAmount(100), Memo: "hi", Nonce: 7, Delta: -3, Ok: true, Data: []byte{1, 2}}
+	assert.Nil(t, Emit(ctx, &evt))
+	assert.Nil(t, Emit(ctx, &transferEvent{From: from, Amount: abi.NewTokenAmount(0)}))
+
+	events := sim.Events()
+	assert.Len(t, events, 2)
+	actorEvt := events[0]
+	assert.Len(t, actorEvt.Entries, 9)
+	assert.Equal(t, EventTypeKey, actorEvt.Entries[0].Key)
+	assert.Equal(t, types.Flags(types.FLAGINDEXEDALL), actorEvt.Entries[0].Flags)
+	assert.Equal(t, "from", actorEvt.Entries[1].Key)
+	assert.Equal(t, types.Flags(types.FLAGINDEXEDALL), actorEvt.Entries[1].Flags)
+	assert.Equal(t, types.Flags(types.FLAGINDEXEDVALUE), actorEvt.Entries[2].Flags)
+	assert.Equal(t, "amount", actorEvt.Entries[3].Key)
+	assert.Equal(t, types.Flags(0), actorEvt.Entries[3].Flags)
+	for _, entry := range actorEvt.Entries {
+		assert.Equal(t, types.IPLDRAW, entry.Codec)
+	}
+
+	decoded, err := DecodeEvent[transferEvent](actorEvt)
+	assert.Nil(t, err)
+	assert.Equal(t, evt.From, decoded.From)
+	assert.Equal(t, to, *decoded.To)
+	assert.Equal(t, evt.Amount.String(), decoded.Amount.String())
+	assert.Equal(t, "hi", decoded.Memo)
+	assert.Equal(t, uint64(7), decoded.Nonce)
+	assert.Equal(t, int64(-3), decoded.Delta)
+	assert.True(t, decoded.Ok)
+	assert.Equal(t, []byte{1, 2}, decoded.Data)
+
+	// a nil pointer is written as null
+	decoded, err = DecodeEvent[transferEvent](events[1])
+	assert.Nil(t, err)
+	assert.Nil(t, decoded.To)
+
+	_, err = DecodeEvent[approvalEvent](actorEvt)
+	assert.True(t, errors.Is(err, ErrEventMismatch))
+}
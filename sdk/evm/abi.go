@@ -0,0 +1,407 @@
+// Package evm encodes and decodes Solidity ABI data and invokes contracts deployed to the EVM actor. The codec
+// uses type switches rather than reflection so it works with TinyGo.
+//
+// Actors call EVM contracts by CallEVM and InvokeContract. EVM contracts call actors through the call_actor
+// precompile: the actor method takes an ActorCall holding the ABI calldata and returns an ActorReturn holding the
+// ABI encoded return data, both wrapped in cbor byte strings like the params and the return data of the EVM actor.
+package evm
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/eth"
+)
+
+// wordSize the size of an ABI word in bytes.
+const wordSize = 32
+
+// ErrInvalidABI the data can't be decoded as the given ABI types.
+var ErrInvalidABI = errors.New("invalid abi data")
+
+// Array a dynamic array `T[]` argument, its elements are of the same type.
+type Array []interface{}
+
+// Tuple a tuple, eg. a struct, argument.
+type Tuple []interface{}
+
+// Kind the kind of an ABI type.
+type Kind uint8
+
+const (
+	KindUint Kind = iota
+	KindInt
+	KindAddress
+	KindBool
+	KindBytes32
+	KindBytes
+	KindString
+	KindArray
+	KindTuple
+)
+
+// Type an ABI type used to decode data, see Decode.
+type Type struct {
+	Kind   Kind
+	Elem   *Type
+	Fields []Type
+}
+
+var (
+	TypeUint256 = Type{Kind: KindUint}
+	TypeInt256  = Type{Kind: KindInt}
+	TypeAddress = Type{Kind: KindAddress}
+	TypeBool    = Type{Kind: KindBool}
+	TypeBytes32 = Type{Kind: KindBytes32}
+	TypeBytes   = Type{Kind: KindBytes}
+	TypeString  = Type{Kind: KindString}
+)
+
+// ArrayOf returns the dynamic array type `elem[]`.
+func ArrayOf(elem Type) Type {
+	return Type{Kind: KindArray, Elem: &elem}
+}
+
+// TupleOf returns the tuple type `(fields...)`.
+func TupleOf(fields ...Type) Type {
+	return Type{Kind: KindTuple, Fields: fields}
+}
+
+func (t Type) isDynamic() bool {
+	switch t.Kind {
+	case KindBytes, KindString, KindArray:
+		return true
+	case KindTuple:
+		for _, field := range t.Fields {
+			if field.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize the size of the static encoding of a value of type t.
+func (t Type) headSize() int {
+	if t.Kind == KindTuple && !t.isDynamic() {
+		size := 0
+		for _, field := range t.Fields {
+			size += field.headSize()
+		}
+		return size
+	}
+	return wordSize
+}
+
+// Selector returns the function selector of a function signature, eg. "transfer(address,uint256)", hashed by the
+// keccak-256 hash syscall.
+func Selector(ctx context.Context, signature string) ([4]byte, error) {
+	var selector [4]byte
+	hash, err := sdk.HashKeccak256(ctx, []byte(signature))
+	if err != nil {
+		return selector, err
+	}
+	copy(selector[:], hash[:])
+	return selector, nil
+}
+
+// EncodeCall returns the calldata of a call to the function `selector` with arguments `args`.
+func EncodeCall(selector [4]byte, args ...interface{}) ([]byte, error) {
+	data, err := Encode(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(selector[:], data...), nil
+}
+
+// Encode encodes `args` as the tuple of their types. Arguments are bools, unsigned and signed integers as uint256
+// and int256, *big.Int as uint256 or int256 if negative, eth.Address, [32]byte as bytes32, []byte as bytes,
+// strings, Array and Tuple.
+func Encode(args ...interface{}) ([]byte, error) {
+	enc, _, err := encodeTuple(args)
+	return enc, err
+}
+
+// encodeTuple returns the encoding of the tuple `values` and whether it is dynamic, ie. has a dynamic field.
+func encodeTuple(values []interface{}) ([]byte, bool, error) {
+	heads := make([][]byte, len(values))
+	var tails [][]byte
+	headLen := 0
+	for i, value := range values {
+		enc, dynamic, err := encodeValue(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("argument %d: %w", i, err)
+		}
+		if dynamic {
+			tails = append(tails, enc)
+			headLen += wordSize
+			continue
+		}
+		heads[i] = enc
+		headLen += len(enc)
+	}
+
+	out := make([]byte, 0, headLen)
+	offset := headLen
+	tail := 0
+	for i := range values {
+		if heads[i] != nil {
+			out = append(out, heads[i]...)
+			continue
+		}
+		out = append(out, uintWord(uint64(offset))...)
+		offset += len(tails[tail])
+		tail++
+	}
+	for _, enc := range tails {
+		out = append(out, enc...)
+	}
+	return out, len(tails) > 0, nil
+}
+
+// encodeValue returns the encoding of `value` and whether it is dynamic, ie. referenced by an offset.
+func encodeValue(value interface{}) ([]byte, bool, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return uintWord(1), false, nil
+		}
+		return uintWord(0), false, nil
+	case uint8:
+		return uintWord(uint64(v)), false, nil
+	case uint16:
+		return uintWord(uint64(v)), false, nil
+	case uint32:
+		return uintWord(uint64(v)), false, nil
+	case uint64:
+		return uintWord(v), false, nil
+	case uint:
+		return uintWord(uint64(v)), false, nil
+	case int8:
+		return intWord(int64(v)), false, nil
+	case int16:
+		return intWord(int64(v)), false, nil
+	case int32:
+		return intWord(int64(v)), false, nil
+	case int64:
+		return intWord(v), false, nil
+	case int:
+		return intWord(int64(v)), false, nil
+	case *big.Int:
+		word, err := bigWord(v)
+		return word, false, err
+	case eth.Address:
+		word := make([]byte, wordSize)
+		copy(word[wordSize-eth.AddressLength:], v[:])
+		return word, false, nil
+	case [32]byte:
+		return append([]byte(nil), v[:]...), false, nil
+	case []byte:
+		return encodeBytes(v), true, nil
+	case string:
+		return encodeBytes([]byte(v)), true, nil
+	case Array:
+		elems, _, err := encodeTuple(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return append(uintWord(uint64(len(v))), elems...), true, nil
+	case Tuple:
+		return encodeTuple(v)
+	}
+	return nil, false, fmt.Errorf("unsupported abi value type %T", value)
+}
+
+func encodeBytes(data []byte) []byte {
+	padded := (len(data) + wordSize - 1) / wordSize * wordSize
+	out := make([]byte, wordSize+padded)
+	copy(out, uintWord(uint64(len(data))))
+	copy(out[wordSize:], data)
+	return out
+}
+
+func uintWord(v uint64) []byte {
+	word := make([]byte, wordSize)
+	binary.BigEndian.PutUint64(word[wordSize-8:], v)
+	return word
+}
+
+func intWord(v int64) []byte {
+	word := uintWord(uint64(v))
+	if v < 0 {
+		for i := 0; i < wordSize-8; i++ {
+			word[i] = 0xff
+		}
+	}
+	return word
+}
+
+// bigWord encodes `v` as uint256, or as int256 in two's complement if negative.
+func bigWord(v *big.Int) ([]byte, error) {
+	if v == nil {
+		return nil, fmt.Errorf("nil big int")
+	}
+	if v.BitLen() > 256 || (v.Sign() < 0 && v.BitLen() > 255) {
+		return nil, fmt.Errorf("big int %s overflows 256 bits", v)
+	}
+	word := make([]byte, wordSize)
+	if v.Sign() >= 0 {
+		return v.FillBytes(word), nil
+	}
+	twos := new(big.Int).Add(v, new(big.Int).Lsh(big.NewInt(1), 256))
+	return twos.FillBytes(word), nil
+}
+
+// Decode decodes `data` as the tuple of `types`. Values are decoded as *big.Int for uint256 and int256,
+// eth.Address, bool, [32]byte for bytes32, []byte, string, Array and Tuple.
+func Decode(data []byte, types ...Type) ([]interface{}, error) {
+	return decodeTuple(data, 0, types)
+}
+
+// DecodeCall splits calldata into its selector and its arguments decoded as the tuple of `types`, eg. the
+// params of a call from Solidity.
+func DecodeCall(calldata []byte, types ...Type) ([4]byte, []interface{}, error) {
+	var selector [4]byte
+	if len(calldata) < len(selector) {
+		return selector, nil, fmt.Errorf("%w: calldata has length %d", ErrInvalidABI, len(calldata))
+	}
+	copy(selector[:], calldata)
+	args, err := Decode(calldata[len(selector):], types...)
+	return selector, args, err
+}
+
+// decodeTuple decodes the tuple of `types` encoded at `data[base:]`.
+func decodeTuple(data []byte, base int, types []Type) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	pos := base
+	for i, t := range types {
+		if !t.isDynamic() {
+			value, err := decodeValue(data, pos, t)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+			pos += t.headSize()
+			continue
+		}
+		offset, err := readOffset(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeValue(data, base+offset, t)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		pos += wordSize
+	}
+	return values, nil
+}
+
+// decodeValue decodes the value of type `t` encoded at `data[pos:]`.
+func decodeValue(data []byte, pos int, t Type) (interface{}, error) {
+	switch t.Kind {
+	case KindTuple:
+		values, err := decodeTuple(data, pos, t.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return Tuple(values), nil
+	case KindBytes, KindString, KindArray:
+		length, err := readOffset(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == KindArray {
+			// check the heads fit the data before allocating them
+			if length*t.Elem.headSize() > len(data)-(pos+wordSize) {
+				return nil, fmt.Errorf("%w: array of length %d overflows data", ErrInvalidABI, length)
+			}
+			elems := make([]Type, length)
+			for i := range elems {
+				elems[i] = *t.Elem
+			}
+			values, err := decodeTuple(data, pos+wordSize, elems)
+			if err != nil {
+				return nil, err
+			}
+			return Array(values), nil
+		}
+		start := pos + wordSize
+		if start+length > len(data) {
+			return nil, fmt.Errorf("%w: bytes of length %d overflow data", ErrInvalidABI, length)
+		}
+		if t.Kind == KindString {
+			return string(data[start : start+length]), nil
+		}
+		return append([]byte(nil), data[start:start+length]...), nil
+	}
+
+	word, err := readWord(data, pos)
+	if err != nil {
+		return nil, err
+	}
+	switch t.Kind {
+	case KindUint:
+		return new(big.Int).SetBytes(word), nil
+	case KindInt:
+		v := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return v, nil
+	case KindAddress:
+		if !isZero(word[:wordSize-eth.AddressLength]) {
+			return nil, fmt.Errorf("%w: address at %d has dirty padding", ErrInvalidABI, pos)
+		}
+		var addr eth.Address
+		copy(addr[:], word[wordSize-eth.AddressLength:])
+		return addr, nil
+	case KindBool:
+		if !isZero(word[:wordSize-1]) || word[wordSize-1] > 1 {
+			return nil, fmt.Errorf("%w: bool at %d isn't 0 or 1", ErrInvalidABI, pos)
+		}
+		return word[wordSize-1] == 1, nil
+	case KindBytes32:
+		var b [32]byte
+		copy(b[:], word)
+		return b, nil
+	}
+	return nil, fmt.Errorf("unsupported abi type kind %d", t.Kind)
+}
+
+func readWord(data []byte, pos int) ([]byte, error) {
+	if pos < 0 || pos+wordSize > len(data) {
+		return nil, fmt.Errorf("%w: word at %d overflows data of length %d", ErrInvalidABI, pos, len(data))
+	}
+	return data[pos : pos+wordSize], nil
+}
+
+// readOffset reads an offset or a length, which must fit the data.
+func readOffset(data []byte, pos int) (int, error) {
+	word, err := readWord(data, pos)
+	if err != nil {
+		return 0, err
+	}
+	if !isZero(word[:wordSize-8]) {
+		return 0, fmt.Errorf("%w: offset at %d is too large", ErrInvalidABI, pos)
+	}
+	v := binary.BigEndian.Uint64(word[wordSize-8:])
+	if v > uint64(len(data)) {
+		return 0, fmt.Errorf("%w: offset at %d is too large", ErrInvalidABI, pos)
+	}
+	return int(v), nil
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+//go:build simulate
+// +build simulate
+
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/eth"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/stretchr/testify/assert"
+)
+
+func words(ws ...string) string {
+	out := ""
+	for _, w := range ws {
+		out += strings.Repeat("0", 64-len(w)) + w
+	}
+	return out
+}
+
+func TestEncodeCall(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	selector, err := Selector(ctx, "baz(uint32,bool)")
+	assert.Nil(t, err)
+	assert.Equal(t, "cdcd77c0", hex.EncodeToString(selector[:]))
+	calldata, err := EncodeCall(selector, uint32(69), true)
+	assert.Nil(t, err)
+	assert.Equal(t, "cdcd77c0"+words("45", "1"), hex.EncodeToString(calldata))
+
+	selector, err = Selector(ctx, "sam(bytes,bool,uint256[])")
+	assert.Nil(t, err)
+	assert.Equal(t, "a5643bf2", hex.EncodeToString(selector[:]))
+	calldata, err = EncodeCall(selector, []byte("dave"), true, Array{uint64(1), uint64(2), uint64(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, "a5643bf2"+words("60", "1", "a0", "4")+"64617665"+strings.Repeat("0", 56)+words("3", "1", "2", "3"), hex.EncodeToString(calldata))
+
+	_, err = Encode(1.5)
+	assert.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	owner, err := eth.ParseAddress(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	assert.Nil(t, err)
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	var hash [32]byte
+	hash[0] = 0xab
+
+	data, err := Encode(owner, amount, int64(-5), hash, "hello", Tuple{true, Array{"a", "bc"}}, Tuple{uint64(7), false})
+	assert.Nil(t, err)
+
+	values, err := Decode(data, TypeAddress, TypeUint256, TypeInt256, TypeBytes32, TypeString,
+		TupleOf(TypeBool, ArrayOf(TypeString)), TupleOf(TypeUint256, TypeBool))
+	assert.Nil(t, err)
+	assert.Equal(t, owner, values[0])
+	assert.Equal(t, 0, amount.Cmp(values[1].(*big.Int)))
+	assert.Equal(t, int64(-5), values[2].(*big.Int).Int64())
+	assert.Equal(t, hash, values[3])
+	assert.Equal(t, "hello", values[4])
+	assert.Equal(t, Tuple{true, Array{"a", "bc"}}, values[5])
+	assert.Equal(t, uint64(7), values[6].(Tuple)[0].(*big.Int).Uint64())
+	assert.Equal(t, false, values[6].(Tuple)[1])
+
+	_, err = Decode(data[:40], TypeAddress, TypeUint256)
+	assert.True(t, errors.Is(err, ErrInvalidABI))
+
+	for _, bad := range []struct {
+		data string
+		t    Type
+	}{
+		// a bool other than 0 or 1
+		{words("2"), TypeBool},
+		{words("1" + strings.Repeat("0", 62) + "1"), TypeBool},
+		// an address with dirty padding
+		{words("1" + strings.Repeat("0", 63)), TypeAddress},
+		// an array longer than the data holding its heads
+		{words("20", "40"), ArrayOf(TypeUint256)},
+	} {
+		raw, err := hex.DecodeString(bad.data)
+		assert.Nil(t, err)
+		_, err = Decode(raw, bad.t)
+		assert.True(t, errors.Is(err, ErrInvalidABI), bad.data)
+	}
+}
@@ -0,0 +1,130 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// MethodInvokeContract the method of the EVM actor running a contract with calldata, the FRC-42 hash of
+// "InvokeEVM".
+const MethodInvokeContract abi.MethodNum = 3844450837
+
+// CallEVM calls the function `selector` of the contract `to` with arguments `args`, see Encode, and returns the
+// ABI encoded return data, see Decode. A revert is returned as *sdk.CallError holding the revert data.
+func CallEVM(ctx context.Context, to address.Address, selector [4]byte, args ...interface{}) ([]byte, error) {
+	calldata, err := EncodeCall(selector, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode call: %w", err)
+	}
+	return InvokeContract(ctx, to, calldata, big.Zero())
+}
+
+// InvokeContract runs the contract `to` with calldata `calldata` and value `value`, the calldata and the return
+// data are wrapped in the cbor byte string the EVM actor expects.
+func InvokeContract(ctx context.Context, to address.Address, calldata []byte, value abi.TokenAmount, opts ...sdk.SendOption) ([]byte, error) {
+	params, err := WrapBytes(calldata)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := sdk.Send(ctx, to, MethodInvokeContract, params, value, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !receipt.ExitCode.IsSuccess() {
+		revert, _ := UnwrapBytes(receipt.ReturnData)
+		return nil, &sdk.CallError{To: to, Method: MethodInvokeContract, Code: receipt.ExitCode, ReturnData: revert}
+	}
+	if len(receipt.ReturnData) == 0 {
+		return nil, nil
+	}
+	return UnwrapBytes(receipt.ReturnData)
+}
+
+// WrapBytes wraps `data` in a cbor byte string, like the params and the return data of the EVM actor.
+func WrapBytes(data []byte) (types.RawBytes, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := writeByteString(buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeByteString(w io.Writer, data []byte) error {
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajByteString, uint64(len(data))); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// UnwrapBytes reads the data wrapped in a cbor byte string by WrapBytes.
+func UnwrapBytes(raw []byte) ([]byte, error) {
+	data, err := cbg.ReadByteArray(bytes.NewReader(raw), uint64(len(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cbor byte string: %w", err)
+	}
+	return data, nil
+}
+
+// ActorCall the params of a call from an EVM contract into an actor through the call_actor precompile, the ABI
+// calldata of the call wrapped in a cbor byte string like the params of InvokeContract. An actor method called
+// from Solidity takes it as params, eg. func (s *State) OnCall(ctx context.Context, call *evm.ActorCall)
+// (*evm.ActorReturn, error), and decodes the call with Decode.
+type ActorCall struct {
+	Calldata []byte
+}
+
+// Decode splits the calldata into its selector and its arguments decoded as the tuple of `types`, see DecodeCall.
+func (c *ActorCall) Decode(types ...Type) ([4]byte, []interface{}, error) {
+	return DecodeCall(c.Calldata, types...)
+}
+
+func (c *ActorCall) MarshalCBOR(w io.Writer) error {
+	return writeByteString(w, c.Calldata)
+}
+
+func (c *ActorCall) UnmarshalCBOR(r io.Reader) error {
+	calldata, err := cbg.ReadByteArray(r, cbg.ByteArrayMaxLen)
+	if err != nil {
+		return fmt.Errorf("failed to read calldata: %w", err)
+	}
+	c.Calldata = calldata
+	return nil
+}
+
+// ActorReturn the return data of an actor called by an EVM contract, ABI encoded values wrapped in a cbor byte
+// string like the return data of the EVM actor, so the contract decodes it as the return of a contract.
+type ActorReturn struct {
+	Data []byte
+}
+
+// NewActorReturn ABI encodes `values`, see Encode, as the return data of an actor called by an EVM contract.
+func NewActorReturn(values ...interface{}) (*ActorReturn, error) {
+	data, err := Encode(values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode return: %w", err)
+	}
+	return &ActorReturn{Data: data}, nil
+}
+
+func (ret *ActorReturn) MarshalCBOR(w io.Writer) error {
+	return writeByteString(w, ret.Data)
+}
+
+func (ret *ActorReturn) UnmarshalCBOR(r io.Reader) error {
+	data, err := cbg.ReadByteArray(r, cbg.ByteArrayMaxLen)
+	if err != nil {
+		return fmt.Errorf("failed to read return data: %w", err)
+	}
+	ret.Data = data
+	return nil
+}
@@ -0,0 +1,101 @@
+//go:build simulate
+// +build simulate
+
+package evm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	fbig "github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/eth"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallEVM(t *testing.T) {
+	sim, ctx := simulated.CreateEmptySimulator()
+	to, err := address.NewIDAddress(1024)
+	assert.Nil(t, err)
+
+	selector, err := Selector(ctx, "balanceOf(address)")
+	assert.Nil(t, err)
+	calldata, err := EncodeCall(selector, uint64(1))
+	assert.Nil(t, err)
+	params, err := WrapBytes(calldata)
+	assert.Nil(t, err)
+	ret, err := Encode(uint64(42))
+	assert.Nil(t, err)
+	wrappedRet, err := WrapBytes(ret)
+	assert.Nil(t, err)
+	retID, err := sys.Create(ctx, types.DAGCBOR, wrappedRet)
+	assert.Nil(t, err)
+
+	sim.ExpectSend(simulated.SendMock{To: to, Method: MethodInvokeContract, Params: params, Value: fbig.Zero(),
+		Out: types.SendResult{ReturnID: retID, ReturnSize: uint32(len(wrappedRet))}})
+	out, err := CallEVM(ctx, to, selector, uint64(1))
+	assert.Nil(t, err)
+	values, err := Decode(out, TypeUint256)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, big.NewInt(42).Cmp(values[0].(*big.Int)))
+
+	sim.ExpectSend(simulated.SendMock{To: to, Method: MethodInvokeContract, Params: params, Value: fbig.Zero(),
+		Out: types.SendResult{ExitCode: ferrors.USR_ASSERTION_FAILED}})
+	_, err = CallEVM(ctx, to, selector, uint64(1))
+	var callErr *sdk.CallError
+	assert.True(t, errors.As(err, &callErr))
+	assert.Equal(t, ferrors.USR_ASSERTION_FAILED, callErr.Code)
+}
+
+func TestActorCall(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	selector, err := Selector(ctx, "transfer(address,uint256)")
+	assert.Nil(t, err)
+	to := eth.MaskedIDAddress(1024)
+	calldata, err := EncodeCall(selector, to, uint64(100))
+	assert.Nil(t, err)
+	// the params of call_actor, wrapped like those of InvokeContract
+	params, err := WrapBytes(calldata)
+	assert.Nil(t, err)
+
+	var call ActorCall
+	assert.Nil(t, call.UnmarshalCBOR(bytes.NewReader(params)))
+	gotSelector, args, err := call.Decode(TypeAddress, TypeUint256)
+	assert.Nil(t, err)
+	assert.Equal(t, selector, gotSelector)
+	assert.Equal(t, to, args[0])
+	assert.Equal(t, 0, big.NewInt(100).Cmp(args[1].(*big.Int)))
+
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, call.MarshalCBOR(buf))
+	assert.Equal(t, []byte(params), buf.Bytes())
+
+	_, _, err = (&ActorCall{Calldata: []byte{1, 2}}).Decode()
+	assert.True(t, errors.Is(err, ErrInvalidABI))
+	assert.NotNil(t, call.UnmarshalCBOR(bytes.NewReader(calldata)))
+
+	// the return data is read by the caller like the return data of a contract
+	ret, err := NewActorReturn(true, "ok")
+	assert.Nil(t, err)
+	buf.Reset()
+	assert.Nil(t, ret.MarshalCBOR(buf))
+	unwrapped, err := UnwrapBytes(buf.Bytes())
+	assert.Nil(t, err)
+	values, err := Decode(unwrapped, TypeBool, TypeString)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{true, "ok"}, values)
+
+	var decodedRet ActorReturn
+	assert.Nil(t, decodedRet.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, ret.Data, decodedRet.Data)
+
+	_, err = NewActorReturn(struct{}{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,113 @@
+package ferrors
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/cbor"
+)
+
+// ActorError an error returned by an actor method, the generated entry code exits with its code and returns its
+// data to the caller. Data is the cbor encoding of a value describing the error, it may be empty.
+type ActorError struct {
+	Code    ExitCode
+	Message string
+	Data    []byte
+	Cause   error
+}
+
+// NewActorError creates an actor error with exit code `code`.
+func NewActorError(code ExitCode, format string, args ...interface{}) *ActorError {
+	return &ActorError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapActorError creates an actor error with exit code `code` caused by `cause`.
+func WrapActorError(cause error, code ExitCode, format string, args ...interface{}) *ActorError {
+	return &ActorError{Code: code, Message: fmt.Sprintf(format, args...), Cause: cause}
+}
+
+// IllegalArgumentf creates an actor error with exit code USR_ILLEGAL_ARGUMENT.
+func IllegalArgumentf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_ILLEGAL_ARGUMENT, format, args...)
+}
+
+// NotFoundf creates an actor error with exit code USR_NOT_FOUND.
+func NotFoundf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_NOT_FOUND, format, args...)
+}
+
+// Forbiddenf creates an actor error with exit code USR_FORBIDDEN.
+func Forbiddenf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_FORBIDDEN, format, args...)
+}
+
+// InsufficientFundsf creates an actor error with exit code USR_INSUFFICIENT_FUNDS.
+func InsufficientFundsf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_INSUFFICIENT_FUNDS, format, args...)
+}
+
+// IllegalStatef creates an actor error with exit code USR_ILLEGAL_STATE.
+func IllegalStatef(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_ILLEGAL_STATE, format, args...)
+}
+
+// Serializationf creates an actor error with exit code USR_SERIALIZATION.
+func Serializationf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_SERIALIZATION, format, args...)
+}
+
+// UnhandledMessagef creates an actor error with exit code USR_UNHANDLED_MESSAGE.
+func UnhandledMessagef(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_UNHANDLED_MESSAGE, format, args...)
+}
+
+// Unspecifiedf creates an actor error with exit code USR_UNSPECIFIED.
+func Unspecifiedf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_UNSPECIFIED, format, args...)
+}
+
+// AssertionFailedf creates an actor error with exit code USR_ASSERTION_FAILED.
+func AssertionFailedf(format string, args ...interface{}) *ActorError {
+	return NewActorError(USR_ASSERTION_FAILED, format, args...)
+}
+
+// WithData sets the cbor encoding of `data` as the data of the error, an encoding failure is kept as cause.
+func (e *ActorError) WithData(data cbor.Marshaler) *ActorError {
+	buf := bytes.NewBuffer(nil)
+	if err := data.MarshalCBOR(buf); err != nil {
+		e.Cause = fmt.Errorf("failed to marshal error data: %w", err)
+		return e
+	}
+	e.Data = buf.Bytes()
+	return e
+}
+
+// UnmarshalData decodes the data of the error into `data`.
+func (e *ActorError) UnmarshalData(data cbor.Unmarshaler) error {
+	return data.UnmarshalCBOR(bytes.NewReader(e.Data))
+}
+
+// ExitCode returns the code to exit with, codes reserved for the VM are replaced by USR_UNSPECIFIED as actors
+// can't exit with them.
+func (e *ActorError) ExitCode() ExitCode {
+	if e.Code.IsSystemError() {
+		return USR_UNSPECIFIED
+	}
+	return e.Code
+}
+
+// Error return error message with exit code and cause
+func (e *ActorError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s (exit code %d): %s", e.Message, e.Code, e.Cause)
+	}
+	return fmt.Sprintf("%s (exit code %d)", e.Message, e.Code)
+}
+
+// Unwrap returns the exit code and the cause, so errors.As finds the exit code and errors.Is the cause.
+func (e *ActorError) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Code, e.Cause}
+	}
+	return []error{e.Code}
+}
@@ -3,9 +3,11 @@ package ferrors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
 )
 
 func TestNewSysCallError(t *testing.T) {
@@ -17,3 +19,31 @@ func TestExitCodeAs(t *testing.T) {
 	err := NewSysCallError(6, "this is error:")
 	assert.True(t, errors.Is(err, NotFound))
 }
+
+func TestActorError(t *testing.T) {
+	cause := errors.New("no such key")
+	err := WrapActorError(cause, USR_NOT_FOUND, "owner %d", 10)
+	assert.Equal(t, "owner 10 (exit code 17): no such key", err.Error())
+	assert.True(t, errors.Is(err, cause))
+
+	var wrapped error = fmt.Errorf("transfer: %w", err)
+	exitCode := USR_ILLEGAL_STATE
+	assert.True(t, errors.As(wrapped, &exitCode))
+	assert.Equal(t, USR_NOT_FOUND, exitCode)
+
+	var actorErr *ActorError
+	assert.True(t, errors.As(wrapped, &actorErr))
+	assert.Equal(t, USR_NOT_FOUND, actorErr.ExitCode())
+}
+
+func TestActorErrorData(t *testing.T) {
+	data := cbg.CborInt(42)
+	err := Forbiddenf("not allowed").WithData(&data)
+	assert.Nil(t, err.Cause)
+
+	var decoded cbg.CborInt
+	assert.Nil(t, err.UnmarshalData(&decoded))
+	assert.Equal(t, data, decoded)
+
+	assert.Equal(t, USR_UNSPECIFIED, NewActorError(SYS_OUT_OF_GAS, "reserved").ExitCode())
+}
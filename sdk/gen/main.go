@@ -5,6 +5,7 @@ import (
 
 	"github.com/ipfs-force-community/go-fvm-sdk/gen"
 
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/adt"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
 
@@ -21,7 +22,15 @@ func main() {
 		types.InstallParams{},
 		types.Entry{},
 		types.Receipt{},
-		types.InstallReturn{}); err != nil {
+		types.InstallReturn{},
+		types.ExecParams{},
+		types.ExecReturn{},
+		types.Exec4Params{},
+		types.Exec4Return{}); err != nil {
 		log.Fatalf("gen for ../types: %s", err)
 	}
+
+	if err := gen.GenCborType("../adt", "", adt.MapProof{}, adt.MigrationCursor{}); err != nil {
+		log.Fatalf("gen for ../adt: %s", err)
+	}
 }
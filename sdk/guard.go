@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// reentrancyMarker tags the lock block which replaces the state root while a non-reentrant method runs,
+// the lock block is encoded as [reentrancyMarker, state root].
+const reentrancyMarker = "fvm-sdk/reentrancy-guard"
+
+var reentrancyLockPrefix = func() []byte {
+	buf := bytes.NewBuffer(nil)
+	_ = cbg.WriteMajorTypeHeader(buf, cbg.MajArray, 2)
+	_ = cbg.WriteMajorTypeHeader(buf, cbg.MajTextString, uint64(len(reentrancyMarker)))
+	buf.WriteString(reentrancyMarker)
+	return buf.Bytes()
+}()
+
+// EnterNonReentrant locks the state root of the actor until ExitNonReentrant, a nested call into a
+// non-reentrant method aborts with USR_FORBIDDEN meanwhile. Other methods keep working, LoadState and
+// SaveState read and write the state under the lock.
+func EnterNonReentrant(ctx context.Context) {
+	root, err := Root(ctx)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
+	}
+	data, err := Get(ctx, root)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get data: %v", err))
+	}
+	if _, locked := parseReentrancyLock(data); locked {
+		Abort(ctx, ferrors.USR_FORBIDDEN, "reentrant call into a non-reentrant method")
+	}
+	if err = SetRoot(ctx, putReentrancyLock(ctx, root)); err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to set root: %v", err))
+	}
+}
+
+// ExitNonReentrant unlocks the state root locked by EnterNonReentrant.
+func ExitNonReentrant(ctx context.Context) {
+	root, err := Root(ctx)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
+	}
+	data, err := Get(ctx, root)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get data: %v", err))
+	}
+	stateRoot, locked := parseReentrancyLock(data)
+	if !locked {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, "state root is not locked by a non-reentrant method")
+	}
+	if err = SetRoot(ctx, stateRoot); err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to set root: %v", err))
+	}
+}
+
+// NonReentrant runs `fn` with the state root locked, see EnterNonReentrant.
+func NonReentrant(ctx context.Context, fn func() error) error {
+	EnterNonReentrant(ctx)
+	err := fn()
+	ExitNonReentrant(ctx)
+	return err
+}
+
+// relockState returns the root to set for the state saved as `stateRoot`, a new lock block if the current root
+// is locked. The lock is read from the current root rather than kept in memory, so it can't leak into another
+// invocation.
+func relockState(ctx context.Context, stateRoot cid.Cid) cid.Cid {
+	root, err := Root(ctx)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
+	}
+	if !root.Defined() {
+		return stateRoot
+	}
+	data, err := Get(ctx, root)
+	if err != nil {
+		// the root of a new actor may not be stored, it's not a lock block anyway
+		return stateRoot
+	}
+	if _, locked := parseReentrancyLock(data); !locked {
+		return stateRoot
+	}
+	return putReentrancyLock(ctx, stateRoot)
+}
+
+func putReentrancyLock(ctx context.Context, stateRoot cid.Cid) cid.Cid {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(reentrancyLockPrefix)
+	if err := cbg.WriteCid(buf, stateRoot); err != nil {
+		Abort(ctx, ferrors.USR_SERIALIZATION, fmt.Sprintf("failed to write reentrancy lock: %v", err))
+	}
+	lock, err := Put(ctx, 0xb220, 32, types.DAGCBOR, buf.Bytes())
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to put reentrancy lock: %v", err))
+	}
+	return lock
+}
+
+// parseReentrancyLock returns the state root held by a lock block, or false if `data` isn't a lock block.
+func parseReentrancyLock(data []byte) (cid.Cid, bool) {
+	if !bytes.HasPrefix(data, reentrancyLockPrefix) {
+		return cid.Undef, false
+	}
+	stateRoot, err := cbg.ReadCid(bytes.NewReader(data[len(reentrancyLockPrefix):]))
+	if err != nil {
+		return cid.Undef, false
+	}
+	return stateRoot, true
+}
@@ -0,0 +1,85 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonReentrant(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	state := types.CborString("v1")
+	stateRoot := SaveState(ctx, &state)
+
+	EnterNonReentrant(ctx)
+	root, err := Root(ctx)
+	assert.Nil(t, err)
+	data, err := Get(ctx, root)
+	assert.Nil(t, err)
+	locked, isLock := parseReentrancyLock(data)
+	assert.True(t, isLock)
+	assert.Equal(t, stateRoot, locked)
+
+	// the state is read through the lock
+	var loaded types.CborString
+	LoadState(ctx, &loaded)
+	assert.Equal(t, state, loaded)
+
+	// a nested call into a non-reentrant method aborts
+	code, aborted := catchAbort(func() { EnterNonReentrant(ctx) })
+	assert.True(t, aborted)
+	assert.Equal(t, ferrors.USR_FORBIDDEN, code)
+
+	// the state saved under the lock stays locked
+	loaded = "v2"
+	newRoot := SaveState(ctx, &loaded)
+	data, err = Get(ctx, newRoot)
+	assert.Nil(t, err)
+	locked, isLock = parseReentrancyLock(data)
+	assert.True(t, isLock)
+	LoadState(ctx, &loaded)
+	assert.Equal(t, types.CborString("v2"), loaded)
+
+	ExitNonReentrant(ctx)
+	root, err = Root(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, locked, root)
+
+	// once unlocked the state is saved as is
+	loaded = "v3"
+	root = SaveState(ctx, &loaded)
+	data, err = Get(ctx, root)
+	assert.Nil(t, err)
+	_, isLock = parseReentrancyLock(data)
+	assert.False(t, isLock)
+
+	code, aborted = catchAbort(func() { ExitNonReentrant(ctx) })
+	assert.True(t, aborted)
+	assert.Equal(t, ferrors.USR_ILLEGAL_STATE, code)
+}
+
+func TestNonReentrantError(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	state := types.CborString("v1")
+	stateRoot := SaveState(ctx, &state)
+
+	errFailed := errors.New("failed")
+	assert.ErrorIs(t, NonReentrant(ctx, func() error {
+		return errFailed
+	}), errFailed)
+	root, err := Root(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, stateRoot, root)
+
+	// the guard is released, it can be entered again
+	assert.Nil(t, NonReentrant(ctx, func() error {
+		return nil
+	}))
+}
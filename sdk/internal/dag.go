@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// DagStats the blocks reachable from a root, each block counted once.
+type DagStats struct {
+	Blocks uint64
+	Bytes  uint64
+	// Depth the longest chain of links from the root, a root without links has depth 1.
+	Depth int
+}
+
+// BlockLoader loads the data of a block, it returns false for blocks skipped by the walk, which are neither
+// counted nor followed.
+type BlockLoader func(c cid.Cid) ([]byte, bool, error)
+
+// WalkDag counts the blocks reachable from `root` loaded by `load`, following the links of DAG-CBOR blocks.
+func WalkDag(root cid.Cid, load BlockLoader) (DagStats, error) {
+	var stats DagStats
+	depths := make(map[cid.Cid]int)
+	var walk func(c cid.Cid) (int, error)
+	walk = func(c cid.Cid) (int, error) {
+		if depth, ok := depths[c]; ok {
+			return depth, nil
+		}
+		data, ok, err := load(c)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			depths[c] = 0
+			return 0, nil
+		}
+		stats.Blocks++
+		stats.Bytes += uint64(len(data))
+
+		var links []cid.Cid
+		if c.Prefix().Codec == types.DAGCBOR {
+			if err := cbg.ScanForLinks(bytes.NewReader(data), func(link cid.Cid) {
+				links = append(links, link)
+			}); err != nil {
+				return 0, fmt.Errorf("failed to scan links of %v: %w", c, err)
+			}
+		}
+		depth := 0
+		for _, link := range links {
+			d, err := walk(link)
+			if err != nil {
+				return 0, err
+			}
+			if d > depth {
+				depth = d
+			}
+		}
+		depths[c] = depth + 1
+		return depth + 1, nil
+	}
+
+	depth, err := walk(root)
+	if err != nil {
+		return DagStats{}, err
+	}
+	stats.Depth = depth
+	return stats, nil
+}
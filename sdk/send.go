@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"math"
 
-	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
-
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys"
@@ -20,27 +18,28 @@ type sendCfg struct {
 }
 
 // SendOption options for set send params
-type SendOption func(cfg sendCfg)
+type SendOption func(cfg *sendCfg)
 
 // WithGasLimit used to set gas limit for send call
 func WithGasLimit(gasLimit uint64) SendOption {
-	return func(cfg sendCfg) {
+	return func(cfg *sendCfg) {
 		cfg.gasLimit = &gasLimit
 	}
 }
 
 // WithReadonly used to set readonly mode for send call
 func WithReadonly() SendOption {
-	return func(cfg sendCfg) {
+	return func(cfg *sendCfg) {
 		cfg.flags = types.ReadonlyFlag
 	}
 }
 
-// Send call another actor
+// Send call another actor, Receipt.GasUsed is the gas the call consumed, measured from the available gas
+// before and after it.
 func Send(ctx context.Context, to address.Address, method abi.MethodNum, params types.RawBytes, value abi.TokenAmount, opts ...SendOption) (*types.Receipt, error) {
 	cfg := sendCfg{}
 	for _, opt := range opts {
-		opt(cfg)
+		opt(&cfg)
 	}
 
 	var (
@@ -56,20 +55,34 @@ func Send(ctx context.Context, to address.Address, method abi.MethodNum, params
 		paramsID = types.NoDataBlockID
 	}
 
+	gasBefore, err := sys.AvailableGas(ctx)
+	if err != nil {
+		return nil, err
+	}
 	send, err := sys.Send(ctx, to, method, paramsID, value, toSysGasLimit(cfg.gasLimit), cfg.flags)
 	if err != nil {
 		return nil, err
 	}
+	gasAfter, err := sys.AvailableGas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the send syscall doesn't report the gas used, only the simulator fills it for a mocked send
+	gasUsed := send.GasUsed
+	if gasUsed == 0 {
+		gasUsed = gasBefore - gasAfter
+	}
 
 	var returnData types.RawBytes
-	if send.ExitCode == ferrors.OK && send.ReturnID != types.NoDataBlockID {
-		readBuf, read, err := sys.Read(ctx, send.ReturnID, 0, send.ReturnSize)
+	// the return data of a failed send is the error data of the callee, if any
+	if send.ReturnID != types.NoDataBlockID {
+		readBuf, remaining, err := sys.Read(ctx, send.ReturnID, 0, send.ReturnSize)
 		if err != nil {
 			return nil, fmt.Errorf("read return_data: %w", err)
 		}
 
-		if read != send.ReturnSize {
-			return nil, fmt.Errorf("read size is not equal to stat-size %v-%v", read, send.ReturnSize)
+		if remaining != 0 {
+			return nil, fmt.Errorf("return_data is larger than its stat-size %v, %v bytes remaining", send.ReturnSize, remaining)
 		}
 
 		returnData = readBuf
@@ -78,7 +91,7 @@ func Send(ctx context.Context, to address.Address, method abi.MethodNum, params
 	return &types.Receipt{
 		ExitCode:   send.ExitCode,
 		ReturnData: returnData,
-		GasUsed:    0,
+		GasUsed:    int64(gasUsed),
 	}, nil
 }
 
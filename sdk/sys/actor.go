@@ -38,6 +38,10 @@ func LookupDelegatedAddress(_ context.Context, actorID abi.ActorID) (address.Add
 	if code != 0 {
 		return address.Undef, ferrors.NewSysCallError(ferrors.ErrorNumber(code), "unexpected address resolution failure: ")
 	}
+	if addrLen == 0 {
+		// the actor has no delegated address
+		return address.Undef, nil
+	}
 	//
 	addr, err := address.NewFromBytes(buf[:addrLen])
 	if err != nil {
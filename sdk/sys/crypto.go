@@ -43,19 +43,44 @@ func VerifySignature(
 	return result == 0, nil
 }
 
-func HashBlake2b(_ context.Context, data []byte) ([32]byte, error) {
+// Hash hashes `data` with the hash function `code` and returns the digest.
+func Hash(_ context.Context, code types.HashCode, data []byte) ([]byte, error) {
+	digestLen := code.DigestLen()
+	if digestLen == 0 {
+		digestLen = 64
+	}
+	digest := make([]byte, digestLen)
 	dataPtr, dataLen := GetSlicePointerAndLen(data)
-	digest := [32]byte{}
-	digestPtr, digestLen := GetSlicePointerAndLen(digest)
-	result := [32]byte{}
-	resultPtr, _ := GetSlicePointerAndLen(result[:])
-	code := cryptoHashBlake2b(resultPtr, 0xb220, dataPtr, dataLen, digestPtr, digestLen)
-	if code != 0 {
-		return result, ferrors.NewSysCallError(ferrors.ErrorNumber(code), "failed to compute blake2b hash")
+	digestPtr, _ := GetSlicePointerAndLen(digest)
+	var written uint32
+	result := cryptoHash(uintptr(unsafe.Pointer(&written)), uint64(code), dataPtr, dataLen, digestPtr, uint32(digestLen))
+	if result != 0 {
+		return nil, ferrors.NewSysCallError(ferrors.ErrorNumber(result), fmt.Sprintf("failed to compute hash %#x", uint64(code)))
+	}
+	return digest[:written], nil
+}
+
+// HashBlake2b hashes `data` using blake2b with 256 bit output, kept for callers predating Hash.
+func HashBlake2b(ctx context.Context, data []byte) ([32]byte, error) {
+	var result [32]byte
+	digest, err := Hash(ctx, types.HashBlake2b256, data)
+	if err != nil {
+		return result, err
 	}
+	copy(result[:], digest)
 	return result, nil
 }
 
+// RecoverSecp256k1PublicKey recovers the uncompressed public key which signed `hash` with `sig`.
+func RecoverSecp256k1PublicKey(_ context.Context, hash [32]byte, sig [65]byte) ([65]byte, error) {
+	var pubkey [65]byte
+	code := cryptoRecoverSecpPublicKey(uintptr(unsafe.Pointer(&pubkey)), uintptr(unsafe.Pointer(&hash)), uintptr(unsafe.Pointer(&sig)))
+	if code != 0 {
+		return pubkey, ferrors.NewSysCallError(ferrors.ErrorNumber(code), "failed to recover secp256k1 public key")
+	}
+	return pubkey, nil
+}
+
 func ComputeUnsealedSectorCid(
 	_ context.Context,
 	proofType abi.RegisteredSealProof,
@@ -17,9 +17,11 @@ package sys
 //export verify_signature
 func cryptoVerifySignature(ret uintptr, sig_type uint32, sigOff uintptr, sigLen uint32, addrOff uintptr, addrLen uint32, plainTextOff uintptr, plainTextLen uint32) uint32
 
-// Hashes input data using blake2b with 256 bit output.
+// Hashes input data using the hash function identified by its multihash code, eg. blake2b-256, sha2-256,
+// keccak-256 or ripemd-160.
 // /
-// The output buffer must be sized to a minimum of 32 bytes.
+// Writes the digest in the provided output buffer, truncated to its length, and returns the length of the
+// written digest.
 // /
 // # Errors
 // /
@@ -29,7 +31,22 @@ func cryptoVerifySignature(ret uintptr, sig_type uint32, sigOff uintptr, sigLen
 //
 //go:wasm-module crypto
 //export hash
-func cryptoHashBlake2b(ret uintptr, hash_code uint64, dataOff uintptr, dataLen uint32, digest_off uintptr, digest_len uint32) uint32
+func cryptoHash(ret uintptr, hash_code uint64, dataOff uintptr, dataLen uint32, digest_off uintptr, digest_len uint32) uint32
+
+// Recovers the uncompressed secp256k1 public key which signed the 32 byte message hash with the 65 byte
+// signature, laid out as r || s || recovery id.
+// /
+// Returns the 65 byte uncompressed public key.
+// /
+// # Errors
+// /
+// | Error             | Reason                                               |
+// |-------------------|------------------------------------------------------|
+// | `IllegalArgument` | signature or hash buffers are invalid                |
+//
+//go:wasm-module crypto
+//export recover_secp_public_key
+func cryptoRecoverSecpPublicKey(ret uintptr, hashOff uintptr, sigOff uintptr) uint32
 
 // Computes an unsealed sector CID (CommD) from its constituent piece CIDs
 // (CommPs) and sizes.
@@ -27,9 +27,27 @@ func VerifySignature(
 	panic(ErrorEnvValid)
 }
 
+func Hash(ctx context.Context, code types.HashCode, data []byte) ([]byte, error) {
+	if env, ok := tryGetSimulator(ctx); ok {
+		return env.Hash(code, data)
+	}
+	panic(ErrorEnvValid)
+}
+
+// HashBlake2b hashes `data` using blake2b with 256 bit output, kept for callers predating Hash.
 func HashBlake2b(ctx context.Context, data []byte) ([32]byte, error) {
+	var result [32]byte
+	digest, err := Hash(ctx, types.HashBlake2b256, data)
+	if err != nil {
+		return result, err
+	}
+	copy(result[:], digest)
+	return result, nil
+}
+
+func RecoverSecp256k1PublicKey(ctx context.Context, hash [32]byte, sig [65]byte) ([65]byte, error) {
 	if env, ok := tryGetSimulator(ctx); ok {
-		return env.HashBlake2b(data)
+		return env.RecoverSecp256k1PublicKey(hash, sig)
 	}
 	panic(ErrorEnvValid)
 }
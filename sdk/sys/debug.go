@@ -8,6 +8,7 @@ import (
 	"unsafe"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
 
 func Enabled(_ context.Context) (bool, error) {
@@ -40,3 +41,8 @@ func StoreArtifact(_ context.Context, name string, data []byte) error {
 
 	return nil
 }
+
+// LogEntry records a leveled log entry, formatted as a debug log line.
+func LogEntry(ctx context.Context, entry types.LogEntry) error {
+	return Log(ctx, entry.String())
+}
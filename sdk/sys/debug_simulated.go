@@ -5,6 +5,8 @@ package sys
 
 import (
 	"context"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
 
 func Enabled(ctx context.Context) (bool, error) {
@@ -27,3 +29,10 @@ func StoreArtifact(ctx context.Context, name string, data []byte) error {
 	}
 	panic(ErrorEnvValid)
 }
+
+func LogEntry(ctx context.Context, entry types.LogEntry) error {
+	if env, ok := tryGetSimulator(ctx); ok {
+		return env.LogEntry(entry)
+	}
+	panic(ErrorEnvValid)
+}
@@ -23,7 +23,7 @@ func Charge(_ context.Context, name string, compute uint64) error {
 
 // AvailableGas current gas
 func AvailableGas(_ context.Context) (uint64, error) {
-	var retptr uint32
+	var retptr uint64
 	code := gasAvailable(uintptr(unsafe.Pointer(&retptr)))
 	if code != 0 {
 		return 0, ferrors.NewSysCallError(ferrors.ErrorNumber(code), "Available is fail")
@@ -33,7 +33,10 @@ func vmMessageContext(ret uintptr) uint32 {
 func cryptoVerifySignature(ret uintptr, sigType uint32, sigOff uintptr, sigLen uint32, addrOff uintptr, addrLen uint32, plainTextOff uintptr, plainTextLen uint32) uint32 {
 	panic("ignore this error, just implement nonfvm for ide working")
 }
-func cryptoHashBlake2b(ret uintptr, hashCode uint64, dataOff uintptr, dataLen uint32, digestOff uintptr, digestLen uint32) uint32 {
+func cryptoHash(ret uintptr, hashCode uint64, dataOff uintptr, dataLen uint32, digestOff uintptr, digestLen uint32) uint32 {
+	panic("ignore this error, just implement nonfvm for ide working")
+}
+func cryptoRecoverSecpPublicKey(ret uintptr, hashOff uintptr, sigOff uintptr) uint32 {
 	panic("ignore this error, just implement nonfvm for ide working")
 }
 func cryptoComputeUnsealedSectorCid(ret uintptr, proofType int64, piecesOff uintptr, pieceLen uint32, cidPtr uintptr, cidLen uint32) uint32 {
@@ -23,7 +23,6 @@ func Send(_ context.Context, to address.Address, method abi.MethodNum, params ui
 	if code != 0 {
 		return nil, ferrors.NewSysCallError(ferrors.ErrorNumber(code), "failed to send")
 	}
-
 	return &types.SendResult{
 		ExitCode:    ferrors.ExitCode(send.ExitCode),
 		ReturnID:    send.ReturnID,
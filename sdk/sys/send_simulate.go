@@ -14,7 +14,7 @@ import (
 
 func Send(ctx context.Context, to address.Address, method abi.MethodNum, params uint32, value abi.TokenAmount, gasLimit uint64, flag uint64) (*types.SendResult, error) {
 	if env, ok := tryGetSimulator(ctx); ok {
-		return env.Send(to, method, params, value)
+		return env.Send(to, method, params, value, gasLimit, flag)
 	}
 	panic(ErrorEnvValid)
 }
@@ -23,10 +23,13 @@ func (fvmSimulator *FvmSimulator) LookupDelegatedAddress(actorID abi.ActorID) (a
 	fvmSimulator.actorLk.Lock()
 	defer fvmSimulator.actorLk.Unlock()
 	for k, v := range fvmSimulator.addressMap {
-		if v == actorID {
+		if v == actorID && k.Protocol() == address.Delegated {
 			return k, nil
 		}
 	}
+	if _, ok := fvmSimulator.actorsMap[actorID]; ok {
+		return address.Undef, nil
+	}
 	return address.Undef, ferrors.NotFound
 }
 
@@ -1,13 +1,22 @@
 package simulated
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
 	"github.com/filecoin-project/go-address"
+	gocrypto "github.com/filecoin-project/go-crypto"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/proof"
 	"github.com/filecoin-project/specs-actors/actors/runtime"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	"github.com/ipfs/go-cid"
+	"github.com/minio/blake2b-simd"
+	"golang.org/x/crypto/ripemd160" //nolint
+	"golang.org/x/crypto/sha3"
 )
 
 func (fvmSimulator *FvmSimulator) VerifySignature(
@@ -18,11 +27,48 @@ func (fvmSimulator *FvmSimulator) VerifySignature(
 	panic("This is not implement")
 }
 
+// HashBlake2b hashes `data` using blake2b with 256 bit output, like Hash with types.HashBlake2b256.
 func (fvmSimulator *FvmSimulator) HashBlake2b(data []byte) ([32]byte, error) {
-	result := blakehash(data)
-	var temp [32]byte
-	copy(temp[:], result[:32])
-	return temp, nil
+	var result [32]byte
+	digest, err := fvmSimulator.Hash(types.HashBlake2b256, data)
+	if err != nil {
+		return result, err
+	}
+	copy(result[:], digest)
+	return result, nil
+}
+
+// Hash hashes `data` with the hash function `code`, like the hash syscall.
+func (fvmSimulator *FvmSimulator) Hash(code types.HashCode, data []byte) ([]byte, error) {
+	var hasher hash.Hash
+	switch code {
+	case types.HashSha256:
+		hasher = sha256.New()
+	case types.HashKeccak256:
+		hasher = sha3.NewLegacyKeccak256()
+	case types.HashRipemd160:
+		hasher = ripemd160.New()
+	case types.HashBlake2b256:
+		hasher = blake2b.New256()
+	case types.HashBlake2b512:
+		hasher = blake2b.New512()
+	default:
+		return nil, ferrors.NewSysCallError(ferrors.IllegalArgument, fmt.Sprintf("unsupported hash code %#x", uint64(code)))
+	}
+	hasher.Write(data) //nolint
+	return hasher.Sum(nil), nil
+}
+
+// RecoverSecp256k1PublicKey recovers the uncompressed public key which signed `hash` with `sig`, like the
+// recover_secp_public_key syscall.
+func (fvmSimulator *FvmSimulator) RecoverSecp256k1PublicKey(hash [32]byte, sig [65]byte) ([65]byte, error) {
+	var pubkey [65]byte
+	recovered, err := gocrypto.EcRecover(hash[:], sig[:])
+	if err != nil {
+		return pubkey, ferrors.NewSysCallError(ferrors.IllegalArgument, fmt.Sprintf("failed to recover secp256k1 public key: %v", err))
+	}
+	copy(pubkey[:], recovered)
+	return pubkey, nil
 }
 
 func (fvmSimulator *FvmSimulator) ComputeUnsealedSectorCid(
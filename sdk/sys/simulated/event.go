@@ -5,3 +5,8 @@ import "github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 func (fvmSimulator *FvmSimulator) AppendEvent(event types.ActorEvent) {
 	fvmSimulator.events = append(fvmSimulator.events, event)
 }
+
+// Events returns the events emitted by the actor so far, in order.
+func (fvmSimulator *FvmSimulator) Events() []types.ActorEvent {
+	return fvmSimulator.events
+}
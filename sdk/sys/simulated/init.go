@@ -0,0 +1,102 @@
+package simulated
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/builtin"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/ipfs/go-cid"
+)
+
+// firstNonSingletonActorID the first id the Init actor assigns to created actors.
+const firstNonSingletonActorID abi.ActorID = 100
+
+// isInitExec whether a send is an Exec or Exec4 call to the Init actor which the simulator handles itself, ie.
+// no expected send is queued for the Init actor.
+func (fvmSimulator *FvmSimulator) isInitExec(to address.Address, method abi.MethodNum) bool {
+	if method != types.MethodExec && method != types.MethodExec4 {
+		return false
+	}
+	if id, err := fvmSimulator.ResolveAddress(to); err != nil || id != types.InitActorID {
+		return false
+	}
+	return len(fvmSimulator.sendList) == 0 || fvmSimulator.sendList[0].To != to
+}
+
+// execInit creates an actor like the Init actor does for Exec and Exec4 and returns its addresses, the
+// constructor of the new actor isn't run. Exec4 is forbidden unless the caller is the Ethereum address manager.
+func (fvmSimulator *FvmSimulator) execInit(method abi.MethodNum, params []byte) (*types.SendResult, error) {
+	var (
+		code       cid.Cid
+		subAddress []byte
+	)
+	if method == types.MethodExec {
+		execParams := types.ExecParams{}
+		if err := execParams.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			return &types.SendResult{ExitCode: ferrors.USR_ILLEGAL_ARGUMENT}, nil
+		}
+		code = execParams.CodeCID
+	} else {
+		// like the Init actor, only the Ethereum address manager may assign f4 addresses
+		if fvmSimulator.messageCtx.Receiver != abi.ActorID(types.EthereumAddressManagerActorID) {
+			return &types.SendResult{ExitCode: ferrors.USR_FORBIDDEN}, nil
+		}
+		exec4Params := types.Exec4Params{}
+		if err := exec4Params.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			return &types.SendResult{ExitCode: ferrors.USR_ILLEGAL_ARGUMENT}, nil
+		}
+		code, subAddress = exec4Params.CodeCID, exec4Params.SubAddress
+	}
+
+	actorID := fvmSimulator.nextActorID()
+	idAddr, err := address.NewIDAddress(uint64(actorID))
+	if err != nil {
+		return nil, err
+	}
+	seed := make([]byte, 16)
+	binary.BigEndian.PutUint64(seed, uint64(fvmSimulator.messageCtx.Receiver))
+	binary.BigEndian.PutUint64(seed[8:], uint64(actorID))
+	robustAddr, err := address.NewActorAddress(seed)
+	if err != nil {
+		return nil, err
+	}
+	addrs := []address.Address{idAddr, robustAddr}
+
+	var ret cbor.Marshaler = &types.ExecReturn{IDAddress: idAddr, RobustAddress: robustAddr}
+	if method == types.MethodExec4 {
+		f4Addr, err := address.NewDelegatedAddress(uint64(fvmSimulator.messageCtx.Receiver), subAddress)
+		if err != nil {
+			return &types.SendResult{ExitCode: ferrors.USR_ILLEGAL_ARGUMENT}, nil
+		}
+		addrs = append(addrs, f4Addr)
+		ret = &types.Exec4Return{IDAddress: idAddr, RobustAddress: robustAddr}
+	}
+	for _, addr := range addrs {
+		fvmSimulator.SetActor(actorID, addr, builtin.Actor{Code: code})
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := ret.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	retID := fvmSimulator.blockCreate(types.DAGCBOR, buf.Bytes())
+	return &types.SendResult{ExitCode: ferrors.OK, ReturnID: retID, ReturnCodec: types.DAGCBOR, ReturnSize: uint32(buf.Len())}, nil
+}
+
+// nextActorID returns the id the Init actor assigns to the next created actor.
+func (fvmSimulator *FvmSimulator) nextActorID() abi.ActorID {
+	fvmSimulator.actorLk.Lock()
+	defer fvmSimulator.actorLk.Unlock()
+	next := firstNonSingletonActorID
+	for id := range fvmSimulator.actorsMap {
+		if id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
@@ -16,10 +16,40 @@ type SendMock struct {
 	Out    types.SendResult
 }
 
-func (fvmSimulator *FvmSimulator) Send(to address.Address, method abi.MethodNum, params uint32, value abi.TokenAmount) (*types.SendResult, error) {
+// SentMessage a message sent by the actor, with the options of the send.
+type SentMessage struct {
+	To       address.Address
+	Method   abi.MethodNum
+	Params   []byte
+	Value    big.Int
+	GasLimit uint64
+	Flags    types.SendFlags
+}
+
+func (fvmSimulator *FvmSimulator) Send(to address.Address, method abi.MethodNum, params uint32, value abi.TokenAmount, gasLimit uint64, flags uint64) (*types.SendResult, error) {
+	var data []byte
+	if rawParams, err := fvmSimulator.getBlock(params); err == nil && rawParams != nil {
+		data = rawParams.data
+	}
+	fvmSimulator.sent = append(fvmSimulator.sent, SentMessage{
+		To:       to,
+		Method:   method,
+		Params:   data,
+		Value:    value,
+		GasLimit: gasLimit,
+		Flags:    flags,
+	})
+	if fvmSimulator.isInitExec(to, method) {
+		return fvmSimulator.execInit(method, data)
+	}
 	return fvmSimulator.sendMatch(to, method, params, value)
 }
 
+// SentMessages returns the messages sent by the actor so far, in order.
+func (fvmSimulator *FvmSimulator) SentMessages() []SentMessage {
+	return fvmSimulator.sent
+}
+
 func (fvmSimulator *FvmSimulator) ExpectSend(mock ...SendMock) {
 	fvmSimulator.sendList = append(fvmSimulator.sendList, mock...)
 
@@ -20,3 +20,21 @@ func TestSetSend(t *testing.T) {
 		t.Errorf("match is failed")
 	}
 }
+
+func TestSendRecordsOptions(t *testing.T) {
+	data := []byte{1, 2, 3}
+	defaultfsm := FvmSimulator{}
+	blkId := defaultfsm.blockCreate(types.DAGCBOR, data)
+	defaultfsm.ExpectSend(SendMock{To: address.Undef, Method: 2, Params: data, Value: big.NewInt(3), Out: types.SendResult{GasUsed: 10}})
+	ret, err := defaultfsm.Send(address.Undef, 2, blkId, big.NewInt(3), 1000, types.ReadonlyFlag)
+	if err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if ret.GasUsed != 10 {
+		t.Errorf("gas used expect 10 actual %d", ret.GasUsed)
+	}
+	sent := defaultfsm.SentMessages()
+	if len(sent) != 1 || sent[0].GasLimit != 1000 || sent[0].Flags != types.ReadonlyFlag || sent[0].Method != 2 {
+		t.Errorf("send options not recorded: %+v", sent)
+	}
+}
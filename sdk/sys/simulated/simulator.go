@@ -46,8 +46,20 @@ func (fvmSimulator *FvmSimulator) GetCodeCidForType(actorT types.ActorType) (cid
 	return EmbeddedBuiltinActors[actstr], nil
 }
 
+// ExitPanic the value of the panic which stops a simulated actor when it exits, see Exit.
+type ExitPanic struct {
+	Code ferrors.ExitCode
+	Data []byte
+	Msg  string
+}
+
+func (p ExitPanic) String() string {
+	return fmt.Sprintf("%d:%v %s", p.Code, p.Data, p.Msg)
+}
+
+// Exit stops the actor by panicking with ExitPanic, like the exit syscall which never returns.
 func (fvmSimulator *FvmSimulator) Exit(code ferrors.ExitCode, data []byte, msg string) {
-	panic(fmt.Sprintf("%d:%v %s", code, data, msg))
+	panic(ExitPanic{Code: code, Data: data, Msg: msg})
 }
 
 func (fvmSimulator *FvmSimulator) ExitWithId(code ferrors.ExitCode, blkId types.BlockID, msg string) {
@@ -63,6 +75,19 @@ func (fvmSimulator *FvmSimulator) Log(msg string) error {
 	fmt.Println(msg)
 	return nil
 }
+
+// LogEntry prints a leveled log entry and records it, see LogEntries.
+func (fvmSimulator *FvmSimulator) LogEntry(entry types.LogEntry) error {
+	fmt.Println(entry.String())
+	fvmSimulator.logs = append(fvmSimulator.logs, entry)
+	return nil
+}
+
+// LogEntries returns the leveled log entries recorded by the actor so far, in order.
+func (fvmSimulator *FvmSimulator) LogEntries() []types.LogEntry {
+	return fvmSimulator.logs
+}
+
 func (fvmSimulator *FvmSimulator) StoreArtifact(name string, data []byte) error {
 	fmt.Printf("%s %v\n", name, data)
 	return nil
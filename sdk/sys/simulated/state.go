@@ -71,7 +71,9 @@ type FvmSimulator struct {
 	tipsetCids         map[abi.ChainEpoch]*cid.Cid
 	totalFilCircSupply abi.TokenAmount
 	sendList           []SendMock
+	sent               []SentMessage
 	events             []types.ActorEvent
+	logs               []types.LogEntry
 }
 
 func NewFvmSimulator(callContext *types.MessageContext, networkContext *types.NetworkContext, totalFilCircSupply abi.TokenAmount) *FvmSimulator {
@@ -0,0 +1,42 @@
+package simulated
+
+import (
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/internal"
+	"github.com/ipfs/go-cid"
+)
+
+// StoreStats the number of blocks and bytes in the simulated blockstore.
+type StoreStats struct {
+	Blocks uint64
+	Bytes  uint64
+	// Depth the longest chain of links from the root, only set for reachable stats.
+	Depth int
+}
+
+// StoreStats returns the size of all blocks ever stored in the simulator.
+func (fvmSimulator *FvmSimulator) StoreStats() StoreStats {
+	var stats StoreStats
+	fvmSimulator.ipld.Range(func(key, value interface{}) bool {
+		if _, ok := key.(cid.Cid); ok {
+			stats.Blocks++
+			stats.Bytes += uint64(len(value.([]byte)))
+		}
+		return true
+	})
+	return stats
+}
+
+// ReachableStats returns the size of the blocks reachable from `root`, each block counted once.
+// Only links in DAG-CBOR blocks are followed.
+func (fvmSimulator *FvmSimulator) ReachableStats(root cid.Cid) (StoreStats, error) {
+	stats, err := internal.WalkDag(root, func(c cid.Cid) ([]byte, bool, error) {
+		data, err := fvmSimulator.getData(c)
+		return data, err == nil, err
+	})
+	return StoreStats(stats), err
+}
+
+// StateStats returns the size of the blocks reachable from the state root of the actor.
+func (fvmSimulator *FvmSimulator) StateStats() (StoreStats, error) {
+	return fvmSimulator.ReachableStats(fvmSimulator.rootCid)
+}
@@ -9,11 +9,12 @@ import (
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 	"github.com/ipfs/go-cid"
 	"github.com/minio/blake2b-simd"
+	"github.com/multiformats/go-multihash"
 )
 
 func blakehash(data []byte) []byte {
-	blake := blake2b.New256()
-	return blake.Sum(data)
+	sum := blake2b.Sum256(data)
+	return sum[:]
 }
 
 type emptyInterface struct {
@@ -64,6 +65,14 @@ var (
 	VerifiedRegistryCid = mustParseCid("bafk2bzacectzxvtoselhnzsair5nv6k5vokvegnht6z2lfee4p3xexo4kg4m6")
 )
 
+// the code cids of the FEVM actors, identity cids of their names like the test manifest of the builtin actors
+var (
+	PlaceholderCid = identityCid("fil/10/placeholder")
+	EvmCid         = identityCid("fil/10/evm")
+	EamCid         = identityCid("fil/10/eam")
+	EthAccountCid  = identityCid("fil/10/ethaccount")
+)
+
 var EmbeddedBuiltinActors = map[string]cid.Cid{
 	"account":          mustParseCid("bafk2bzacebmfbtdj5vruje5auacrhhprcjdd6uclhukb7je7t2f6ozfcgqlu2"),
 	"cron":             mustParseCid("bafk2bzacea4gwsbeux7z4yxvpkxpco77iyxijoyqaoikofrxdewunwh3unjem"),
@@ -76,6 +85,18 @@ var EmbeddedBuiltinActors = map[string]cid.Cid{
 	"storagepower":     mustParseCid("bafk2bzaceddmeolsokbxgcr25cuf2skrobtmmoof3dmqfpcfp33lmw63oikvm"),
 	"system":           mustParseCid("bafk2bzaced6kjkbv7lrb2qwq5we2hqaxc6ztch5p52g27qtjy45zdemsk4b7m"),
 	"verifiedregistry": mustParseCid("bafk2bzacectzxvtoselhnzsair5nv6k5vokvegnht6z2lfee4p3xexo4kg4m6"),
+	"placeholder":      PlaceholderCid,
+	"evm":              EvmCid,
+	"eam":              EamCid,
+	"ethaccount":       EthAccountCid,
+}
+
+func identityCid(name string) cid.Cid {
+	ret, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: multihash.IDENTITY, MhLength: -1}.Sum([]byte(name))
+	if err != nil {
+		panic(err)
+	}
+	return ret
 }
 
 func mustParseCid(c string) cid.Cid {
@@ -109,6 +130,14 @@ func actorTypeTostring(actorT types.ActorType) (string, error) {
 		return "reward", nil
 	case types.VerifiedRegistry:
 		return "verifiedregistry", nil
+	case types.PlaceHolder:
+		return "placeholder", nil
+	case types.Evm:
+		return "evm", nil
+	case types.Eam:
+		return "eam", nil
+	case types.EthAccount:
+		return "ethaccount", nil
 	default:
 		return "", ErrorNotFound
 	}
@@ -137,6 +166,14 @@ func stringToactorType(str string) (actorT types.ActorType, err error) {
 		return types.Reward, nil
 	case "verifiedregistry":
 		return types.VerifiedRegistry, nil
+	case "placeholder":
+		return types.PlaceHolder, nil
+	case "evm":
+		return types.Evm, nil
+	case "eam":
+		return types.Eam, nil
+	case "ethaccount":
+		return types.EthAccount, nil
 	default:
 		return types.ActorType(0), ErrorNotFound
 	}
@@ -33,7 +33,7 @@ func VMMessageContext(_ context.Context) (*types.MessageContext, error) {
 // Exit abort actor, panic to stop actor instead of return error
 func Exit(ctx context.Context, code ferrors.ExitCode, data []byte, msg string) {
 	blkId := types.NoDataBlockID
-	if len(data) == 0 {
+	if len(data) > 0 {
 		var err error
 		blkId, err = Create(ctx, types.DAGCBOR, data)
 		if err != nil {
@@ -56,3 +56,9 @@ func ExitWithBlkId(ctx context.Context, code ferrors.ExitCode, blkId types.Block
 		panic("fail to exit " + strconv.Itoa(int(exitCode)))
 	}
 }
+
+// IsExitPanic reports whether a recovered panic is the exit of the actor, the exit syscall never returns so
+// no panic is.
+func IsExitPanic(_ interface{}) bool {
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
@@ -34,3 +35,9 @@ func ExitWithBlkId(ctx context.Context, code ferrors.ExitCode, blkId types.Block
 	}
 	panic(ErrorEnvValid)
 }
+
+// IsExitPanic reports whether a recovered panic is the exit of the simulated actor, see simulated.ExitPanic.
+func IsExitPanic(r interface{}) bool {
+	_, ok := r.(simulated.ExitPanic)
+	return ok
+}
@@ -12,4 +12,32 @@ const (
 
 	BLAKE2B256 uint64 = 0xb220
 	BLAKE2BLEN uint32 = 32
+
+	//EthereumAddressManagerActorID the id of the Ethereum address manager actor, the namespace of the f4
+	//addresses of Ethereum accounts and contracts.
+	EthereumAddressManagerActorID uint64 = 10
+)
+
+// HashCode the multihash code of a hash function supported by the hash syscall.
+type HashCode uint64
+
+const (
+	HashSha256     HashCode = 0x12
+	HashKeccak256  HashCode = 0x1b
+	HashRipemd160  HashCode = 0x1053
+	HashBlake2b256 HashCode = 0xb220
+	HashBlake2b512 HashCode = 0xb240
 )
+
+// DigestLen returns the digest length of the hash function in bytes, 0 if it isn't supported.
+func (c HashCode) DigestLen() int {
+	switch c {
+	case HashSha256, HashKeccak256, HashBlake2b256:
+		return 32
+	case HashRipemd160:
+		return 20
+	case HashBlake2b512:
+		return 64
+	}
+	return 0
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+)
+
 type Flags uint64
 
 const (
@@ -24,3 +30,13 @@ type Entry struct {
 type ActorEvent struct {
 	Entries []*Entry
 }
+
+// EmittedEvent An event emitted by an actor as reported by a node, eg. by an event-index query.
+type EmittedEvent struct {
+	Emitter address.Address
+	Entries []*Entry
+	Height  abi.ChainEpoch
+	MsgCid  cid.Cid
+	// Reverted whether the tipset including the message was reverted.
+	Reverted bool
+}
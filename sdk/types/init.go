@@ -0,0 +1,37 @@
+package types
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+)
+
+const (
+	//InitActorID the id of the Init actor, which creates actors.
+	InitActorID abi.ActorID = 1
+	//MethodExec the method of the Init actor creating an actor, see ExecParams.
+	MethodExec abi.MethodNum = 2
+	//MethodExec4 the method of the Init actor creating an actor with an f4 address, see Exec4Params.
+	MethodExec4 abi.MethodNum = 3
+)
+
+type ExecParams struct {
+	CodeCID           cid.Cid `checked:"true"`
+	ConstructorParams []byte
+}
+
+type ExecReturn struct {
+	IDAddress     address.Address
+	RobustAddress address.Address
+}
+
+type Exec4Params struct {
+	CodeCID           cid.Cid `checked:"true"`
+	ConstructorParams []byte
+	SubAddress        []byte
+}
+
+type Exec4Return struct {
+	IDAddress     address.Address
+	RobustAddress address.Address
+}
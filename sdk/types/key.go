@@ -1,6 +1,10 @@
 package types
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 )
 
@@ -12,6 +16,15 @@ func (k ActorKey) Key() string {
 	return abi.ActorID(k).String()
 }
 
+// ParseActorKey parses the key of an ActorKey.
+func ParseActorKey(k string) (ActorKey, error) {
+	id, err := strconv.ParseUint(k, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid actor id key %v: %w", k, err)
+	}
+	return ActorKey(id), nil
+}
+
 // StringKey Adapts an string as a mapping key.
 type StringKey string
 
@@ -20,6 +33,20 @@ func (k StringKey) Key() string {
 	return string(k)
 }
 
+// ParseStringKey parses the key of a StringKey.
+func ParseStringKey(k string) (StringKey, error) {
+	return StringKey(k), nil
+}
+
+// ParseAddrKey parses the key of an abi.AddrKey.
+func ParseAddrKey(k string) (abi.AddrKey, error) {
+	addr, err := address.NewFromBytes([]byte(k))
+	if err != nil {
+		return abi.AddrKey{}, fmt.Errorf("invalid address key %x: %w", k, err)
+	}
+	return abi.AddrKey(addr), nil
+}
+
 type emptyKeyType struct{}
 
 var SimulatedEnvkey emptyKeyType
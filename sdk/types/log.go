@@ -0,0 +1,76 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LogLevel the severity of a log entry.
+type LogLevel uint8
+
+const (
+	LogTrace LogLevel = iota
+	LogDebug
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogTrace:
+		return "TRACE"
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return "LEVEL(" + strconv.Itoa(int(l)) + ")"
+}
+
+// LogField a key-value pair attached to a log entry, the value is already formatted.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// LogEntry a leveled log entry with fields.
+type LogEntry struct {
+	Level  LogLevel
+	Msg    string
+	Fields []LogField
+}
+
+// Field returns the value of the field keyed `key` and whether there is such a field.
+func (e LogEntry) Field(key string) (string, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// String formats the entry as `[LEVEL] msg key=value ...`, values containing spaces or quotes are quoted.
+func (e LogEntry) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	sb.WriteString(e.Level.String())
+	sb.WriteString("] ")
+	sb.WriteString(e.Msg)
+	for _, f := range e.Fields {
+		sb.WriteByte(' ')
+		sb.WriteString(f.Key)
+		sb.WriteByte('=')
+		if f.Value == "" || strings.ContainsAny(f.Value, " \t\n\"=") {
+			sb.WriteString(strconv.Quote(f.Value))
+		} else {
+			sb.WriteString(f.Value)
+		}
+	}
+	return sb.String()
+}
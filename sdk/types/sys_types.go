@@ -45,6 +45,9 @@ type SendResult struct {
 	ReturnID    BlockID
 	ReturnCodec uint64
 	ReturnSize  uint32
+	// GasUsed the gas charged for the send, the syscall doesn't report it and leaves it zero, sdk.Send
+	// measures it from the available gas
+	GasUsed uint64
 }
 
 type VerifyConsensusFault struct {
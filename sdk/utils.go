@@ -15,7 +15,8 @@ import (
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 )
 
-// SaveState save actor state
+// SaveState save actor state, returns the new state root. The root is a lock block wrapping the state while a
+// non-reentrant method runs, see EnterNonReentrant.
 func SaveState(ctx context.Context, state cbor.Marshaler) cid.Cid {
 	buf := bytes.NewBuffer([]byte{})
 	err := state.MarshalCBOR(buf)
@@ -28,11 +29,12 @@ func SaveState(ctx context.Context, state cbor.Marshaler) cid.Cid {
 		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
 	}
 
-	err = SetRoot(ctx, stCid)
+	root := relockState(ctx, stCid)
+	err = SetRoot(ctx, root)
 	if err != nil {
 		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
 	}
-	return stCid
+	return root
 }
 
 // Constructor construct a acor with initialize state
@@ -52,6 +54,12 @@ func Constructor(ctx context.Context, state cbor.Marshaler) error {
 
 // LoadState loads actors current state
 func LoadState(ctx context.Context, state cbor.Unmarshaler) {
+	_ = loadState(ctx, state)
+}
+
+// loadState loads actors current state and returns the state root, the state is read through the lock block
+// of a non-reentrant method.
+func loadState(ctx context.Context, state cbor.Unmarshaler) cid.Cid {
 	root, err := Root(ctx)
 	if err != nil {
 		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
@@ -61,10 +69,16 @@ func LoadState(ctx context.Context, state cbor.Unmarshaler) {
 	if err != nil {
 		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get data: %v", err))
 	}
+	if stateRoot, locked := parseReentrancyLock(data); locked {
+		if data, err = Get(ctx, stateRoot); err != nil {
+			Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get data: %v", err))
+		}
+	}
 	err = state.UnmarshalCBOR(bytes.NewReader(data))
 	if err != nil {
 		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get data: %v", err))
 	}
+	return root
 }
 
 // LoadStateFromCid load actor state by message cid
@@ -79,6 +93,26 @@ func LoadStateFromCid(ctx context.Context, cid cid.Cid, state cbor.Unmarshaler)
 	}
 }
 
+// Transaction loads the actor state into `state`, runs `fn` to modify it and saves it. The state is only saved
+// if `fn` returns nil, and the actor aborts with USR_ILLEGAL_STATE if the root was changed while `fn` was
+// running, eg. by a re-entrant call during a send, rather than overwriting that change.
+func Transaction(ctx context.Context, state cbor.Er, fn func() error) error {
+	root := loadState(ctx, state)
+	if err := fn(); err != nil {
+		return err
+	}
+
+	current, err := Root(ctx)
+	if err != nil {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("failed to get root: %v", err))
+	}
+	if current != root {
+		Abort(ctx, ferrors.USR_ILLEGAL_STATE, fmt.Sprintf("state root changed during transaction from %s to %s", root, current))
+	}
+	_ = SaveState(ctx, state)
+	return nil
+}
+
 // this code was from https://github.com/modern-go/reflect2/blob/2b33151c9bbc5231aea69b8861c540102b087070/reflect2.go#L238, and unable to use this package directly for now
 type eface struct {
 	_    unsafe.Pointer
@@ -105,6 +139,9 @@ type MethodInfo struct {
 	Func interface{}
 	// indicate whether this method is a readonly function,  not need to send message when invoke this query state
 	Readonly bool
+	// the generated entry code locks the state root while the method runs, a nested call into a non-reentrant
+	// method aborts with USR_FORBIDDEN, see EnterNonReentrant
+	NonReentrant bool
 }
 
 // WriteCborArray marshal cbor array to bytes
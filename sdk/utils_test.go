@@ -0,0 +1,75 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// catchAbort runs `fn` and returns the exit code if it aborted the simulated actor.
+func catchAbort(fn func()) (code ferrors.ExitCode, aborted bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			exit, ok := r.(simulated.ExitPanic)
+			if !ok {
+				panic(r)
+			}
+			code, aborted = exit.Code, true
+		}
+	}()
+	fn()
+	return 0, false
+}
+
+func TestTransaction(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+	state := types.CborString("v1")
+	root := SaveState(ctx, &state)
+
+	var loaded types.CborString
+	assert.Nil(t, Transaction(ctx, &loaded, func() error {
+		assert.Equal(t, types.CborString("v1"), loaded)
+		loaded = "v2"
+		return nil
+	}))
+	newRoot, err := Root(ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, root, newRoot)
+	LoadState(ctx, &loaded)
+	assert.Equal(t, types.CborString("v2"), loaded)
+
+	// a failing transaction leaves the state untouched
+	errFailed := errors.New("failed")
+	assert.ErrorIs(t, Transaction(ctx, &loaded, func() error {
+		loaded = "v3"
+		return errFailed
+	}), errFailed)
+	current, err := Root(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, newRoot, current)
+	LoadState(ctx, &loaded)
+	assert.Equal(t, types.CborString("v2"), loaded)
+
+	// the root changed under the transaction, eg. by a re-entrant call
+	code, aborted := catchAbort(func() {
+		_ = Transaction(ctx, &loaded, func() error {
+			if err := SetRoot(ctx, root); err != nil {
+				return err
+			}
+			loaded = "v4"
+			return nil
+		})
+	})
+	assert.True(t, aborted)
+	assert.Equal(t, ferrors.USR_ILLEGAL_STATE, code)
+	current, err = Root(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, root, current)
+}
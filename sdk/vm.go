@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ipfs-force-community/go-fvm-sdk/sdk/types"
 
@@ -27,3 +28,30 @@ func Exit(ctx context.Context, code ferrors.ExitCode, data []byte, msg string) {
 func ExitWithBlkId(ctx context.Context, code ferrors.ExitCode, blkId types.BlockID, msg string) {
 	sys.ExitWithBlkId(ctx, code, blkId, msg)
 }
+
+// PanicExitCode the exit code of an actor method which panics, an actor may change it in an init function.
+var PanicExitCode = ferrors.USR_ASSERTION_FAILED
+
+// RecoverPanic aborts with PanicExitCode and the panic message if the actor panics, the generated entry code
+// defers it in Invoke. The panic is also logged if debugging is enabled. The exit of a simulated actor, which
+// panics, is passed on unchanged so Abort keeps its exit code.
+//
+// Actors don't recover yet: TinyGo, which builds them, doesn't implement recover on WebAssembly. Up to v0.40
+// its compiler disables recover for wasm32 as it would need the WebAssembly exception handling proposal, which
+// the FVM doesn't support either, so bumping TinyGo doesn't help. On chain recover returns nil and a panic
+// traps, the FVM then fails the message with SYS_ILLEGAL_INSTRUCTION instead of PanicExitCode. The recovery
+// only takes effect in simulated tests.
+func RecoverPanic(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if sys.IsExitPanic(r) {
+		panic(r)
+	}
+	msg := fmt.Sprintf("actor panic: %v", r)
+	if logger, err := NewLogger(); err == nil {
+		logger.Log(ctx, msg)
+	}
+	Abort(ctx, PanicExitCode, msg)
+}
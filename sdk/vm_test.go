@@ -0,0 +1,47 @@
+//go:build simulate
+// +build simulate
+
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/ferrors"
+	"github.com/ipfs-force-community/go-fvm-sdk/sdk/sys/simulated"
+	"github.com/stretchr/testify/assert"
+)
+
+// invoke runs `method` like the generated Invoke entry point.
+func invoke(ctx context.Context, method func()) {
+	defer RecoverPanic(ctx)
+	method()
+}
+
+func TestRecoverPanic(t *testing.T) {
+	_, ctx := simulated.CreateEmptySimulator()
+
+	code, aborted := catchAbort(func() {
+		invoke(ctx, func() {
+			var m map[string]int
+			m["key"] = 1
+		})
+	})
+	assert.True(t, aborted)
+	assert.Equal(t, PanicExitCode, code)
+
+	// an abort passes through unchanged
+	code, aborted = catchAbort(func() {
+		invoke(ctx, func() {
+			Abort(ctx, ferrors.USR_FORBIDDEN, "forbidden")
+		})
+	})
+	assert.True(t, aborted)
+	assert.Equal(t, ferrors.USR_FORBIDDEN, code)
+
+	code, aborted = catchAbort(func() {
+		invoke(ctx, func() {})
+	})
+	assert.False(t, aborted)
+	assert.Equal(t, ferrors.ExitCode(0), code)
+}
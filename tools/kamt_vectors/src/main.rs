@@ -0,0 +1,230 @@
+//! Generates the KAMT test vectors checked by TestKamtVectors in sdk/adt, so the Go Kamt is
+//! compared with the fvm_ipld_kamt used by the builtin EVM actor rather than with hand written
+//! encodings.
+//!
+//! Run from the repository root:
+//!
+//!     cargo run -p kamt_vectors > sdk/adt/testdata/kamt_vectors.json
+
+use std::borrow::Cow;
+use std::cell::RefCell;
+use std::collections::BTreeMap;
+
+use cid::Cid;
+use fvm_ipld_blockstore::Blockstore;
+use fvm_ipld_encoding::{BytesDe, BytesSer};
+use fvm_ipld_kamt::{AsHashedKey, Config, Kamt};
+use serde::{Deserialize, Deserializer, Serialize, Serializer};
+
+/// A 256-bit key serialized like the EVM actor's U256, as big-endian bytes without leading zeros.
+#[derive(Clone, Copy, Debug, PartialEq, Eq, PartialOrd, Ord)]
+struct Key([u8; 32]);
+
+impl Key {
+    fn from_u64(i: u64) -> Self {
+        let mut k = [0u8; 32];
+        k[24..].copy_from_slice(&i.to_be_bytes());
+        Key(k)
+    }
+}
+
+impl Serialize for Key {
+    fn serialize<S: Serializer>(&self, s: S) -> Result<S::Ok, S::Error> {
+        let start = self.0.iter().position(|b| *b != 0).unwrap_or(32);
+        BytesSer(&self.0[start..]).serialize(s)
+    }
+}
+
+impl<'de> Deserialize<'de> for Key {
+    fn deserialize<D: Deserializer<'de>>(d: D) -> Result<Self, D::Error> {
+        let BytesDe(b) = BytesDe::deserialize(d)?;
+        if b.len() > 32 {
+            return Err(serde::de::Error::custom("key longer than 32 bytes"));
+        }
+        let mut k = [0u8; 32];
+        k[32 - b.len()..].copy_from_slice(&b);
+        Ok(Key(k))
+    }
+}
+
+/// Keys are their own hash, as for the EVM actor storage.
+struct Identity;
+
+impl AsHashedKey<Key, 32> for Identity {
+    fn as_hashed_key(key: &Key) -> Cow<[u8; 32]> {
+        Cow::Owned(key.0)
+    }
+}
+
+/// A blockstore listing the blocks written to it.
+#[derive(Default)]
+struct RecordingStore {
+    blocks: RefCell<BTreeMap<Cid, Vec<u8>>>,
+}
+
+impl Blockstore for RecordingStore {
+    fn get(&self, k: &Cid) -> anyhow::Result<Option<Vec<u8>>> {
+        Ok(self.blocks.borrow().get(k).cloned())
+    }
+
+    fn put_keyed(&self, k: &Cid, block: &[u8]) -> anyhow::Result<()> {
+        self.blocks.borrow_mut().insert(*k, block.to_vec());
+        Ok(())
+    }
+}
+
+#[derive(Serialize)]
+struct Op {
+    /// the key in hex, 32 bytes
+    key: String,
+    /// the value put at the key, absent for a delete
+    #[serde(skip_serializing_if = "Option::is_none")]
+    value: Option<u64>,
+}
+
+#[derive(Serialize)]
+struct Vector {
+    name: String,
+    bit_width: u32,
+    max_array_width: usize,
+    ops: Vec<Op>,
+    root: String,
+    /// the blocks of the kamt, hex encoded and keyed by cid
+    blocks: BTreeMap<String, String>,
+}
+
+enum Action {
+    Put(Key, u64),
+    Delete(Key),
+}
+
+fn vector(
+    name: &str,
+    bit_width: u32,
+    max_array_width: usize,
+    actions: Vec<Action>,
+) -> anyhow::Result<Vector> {
+    let store = RecordingStore::default();
+    let conf = Config {
+        bit_width,
+        min_data_depth: 0,
+        max_array_width,
+    };
+    let mut kamt: Kamt<&RecordingStore, Key, u64, Identity> = Kamt::new_with_config(&store, conf);
+    let mut ops = Vec::with_capacity(actions.len());
+    for action in actions {
+        match action {
+            Action::Put(k, v) => {
+                kamt.set(k, v)?;
+                ops.push(Op {
+                    key: hex::encode(k.0),
+                    value: Some(v),
+                });
+            }
+            Action::Delete(k) => {
+                kamt.delete(&k)?;
+                ops.push(Op {
+                    key: hex::encode(k.0),
+                    value: None,
+                });
+            }
+        }
+    }
+    let root = kamt.flush()?;
+
+    // the kamt only writes its nodes when flushed, so the store holds the blocks of root
+    let blocks = store
+        .blocks
+        .borrow()
+        .iter()
+        .map(|(c, block)| (c.to_string(), hex::encode(block)))
+        .collect();
+
+    Ok(Vector {
+        name: name.to_string(),
+        bit_width,
+        max_array_width,
+        ops,
+        root: root.to_string(),
+        blocks,
+    })
+}
+
+/// splitmix64, so the keys are the same on every run without a rand dependency.
+fn next(state: &mut u64) -> u64 {
+    *state = state.wrapping_add(0x9e3779b97f4a7c15);
+    let mut z = *state;
+    z = (z ^ (z >> 30)).wrapping_mul(0xbf58476d1ce4e5b9);
+    z = (z ^ (z >> 27)).wrapping_mul(0x94d049bb133111eb);
+    z ^ (z >> 31)
+}
+
+fn random_keys(seed: u64, n: usize) -> Vec<Key> {
+    let mut state = seed;
+    (0..n)
+        .map(|i| {
+            // mix full width keys with small ones, like EVM storage slots
+            if i % 3 == 0 {
+                return Key::from_u64(next(&mut state) % 1024);
+            }
+            let mut k = [0u8; 32];
+            for chunk in k.chunks_mut(8) {
+                chunk.copy_from_slice(&next(&mut state).to_be_bytes());
+            }
+            Key(k)
+        })
+        .collect()
+}
+
+fn main() -> anyhow::Result<()> {
+    let mut vectors = vec![
+        vector("empty", 5, 1, vec![])?,
+        vector("one key", 5, 1, vec![Action::Put(Key::from_u64(1), 7)])?,
+        vector(
+            "extension",
+            5,
+            1,
+            vec![
+                Action::Put(Key::from_u64(1), 7),
+                Action::Put(Key::from_u64(2), 7),
+            ],
+        )?,
+        vector(
+            "bit width 8",
+            8,
+            1,
+            vec![
+                Action::Put(Key::from_u64(0x0100), 7),
+                Action::Put(Key::from_u64(0x0200), 7),
+            ],
+        )?,
+        vector(
+            "last bit",
+            5,
+            1,
+            vec![
+                Action::Put(Key::from_u64(0), 7),
+                Action::Put(Key::from_u64(1), 7),
+                Action::Put(Key::from_u64(2), 7),
+            ],
+        )?,
+    ];
+
+    for (name, bit_width, max_array_width, seed) in [
+        ("random", 5, 1, 1),
+        ("random wide buckets", 5, 3, 2),
+        ("random bit width 8", 8, 1, 3),
+    ] {
+        let keys = random_keys(seed, 300);
+        let mut actions: Vec<Action> = keys
+            .iter()
+            .enumerate()
+            .map(|(i, k)| Action::Put(*k, i as u64))
+            .collect();
+        actions.extend(keys.iter().step_by(2).map(|k| Action::Delete(*k)));
+        vectors.push(vector(name, bit_width, max_array_width, actions)?);
+    }
+
+    println!("{}", serde_json::to_string_pretty(&vectors)?);
+    Ok(())
+}